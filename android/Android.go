@@ -0,0 +1,439 @@
+package android
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+type Options struct {
+	// IncludeFuzzy also writes fuzzy entries, which otherwise fall back to the default strings.xml.
+	IncludeFuzzy bool
+}
+
+type ConversionError struct {
+	Name   string
+	Reason string
+}
+
+func (e ConversionError) Error() string {
+	return fmt.Sprintf("Failed to convert '%v': %v", e.Name, e.Reason)
+}
+
+var resourceNameValidator = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// ValuesDirectory is the resource directory strings.xml goes in for the language, such as "values-pt-rBR",
+// or, for languages with scripts, "values-b+sr+Latn".
+func ValuesDirectory(tag language.Tag) string {
+	base, script, region := tag.Raw()
+	if script != (language.Script{}) {
+		result := fmt.Sprint("values-b+", base, "+", script)
+		if region != (language.Region{}) {
+			result = fmt.Sprint(result, "+", region)
+		}
+		return result
+	}
+
+	result := fmt.Sprint("values-", base)
+	if region != (language.Region{}) {
+		result = fmt.Sprint(result, "-r", region)
+	}
+	return result
+}
+
+// Write writes the translations of the document as a strings.xml, like android2po does,
+// where the msgctxt is the resource name and the msgid is the source string.
+// Entries without a msgctxt use the msgid as the name, which then needs to be a valid resource name.
+// Plural entries become <plurals>, with an item per plural category of the document, and extracted comments become xml comments.
+// Untranslated and obsolete entries are skipped, so that android falls back to the default resources.
+func Write(w io.Writer, d *gettext.Document, options Options) error {
+	plurals := sync.OnceValues(d.Header.PluralMapping)
+
+	// the xml encoder escapes quotes as &#34; and &#39;, which makes for hard to read resources,
+	// so writing is done by hand
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString("<resources>\n")
+
+	for _, entry := range d.Entries {
+		if len(entry.Id) == 0 || entry.IsObsolete || (entry.Header.IsFuzzy() && !options.IncludeFuzzy) {
+			continue
+		}
+
+		name := entry.Id
+		if entry.IsContextual {
+			name = entry.Context
+		}
+		if !resourceNameValidator.MatchString(name) {
+			return ConversionError{name, "Not a valid resource name. Entries should have the resource name as the msgctxt."}
+		}
+
+		if !entry.IsPlural {
+			if len(entry.Value) == 0 {
+				continue
+			}
+			writeComments(&sb, entry.Header.ExtractedComments)
+			fmt.Fprintf(&sb, "    <string name=\"%v\">%v</string>\n", name, escapeValue(entry.Value))
+			continue
+		}
+
+		plurals, err := plurals()
+		if err != nil {
+			return err
+		}
+		if err := plurals.ValidatePluralCount(&entry); err != nil {
+			return err
+		}
+		if !entry.IsTranslated() {
+			continue
+		}
+
+		writeComments(&sb, entry.Header.ExtractedComments)
+		fmt.Fprintf(&sb, "    <plurals name=\"%v\">\n", name)
+		for _, pluralType := range plurals.Categories() {
+			value := plurals.Value(entry.PluralValues, pluralType)
+			fmt.Fprintf(&sb, "        <item quantity=\"%v\">%v</item>\n", pluralType.Category(), escapeValue(value))
+		}
+		sb.WriteString("    </plurals>\n")
+	}
+
+	sb.WriteString("</resources>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeComments(sb *strings.Builder, comments []string) {
+	for _, comment := range comments {
+		// -- isn't allowed in xml comments
+		fmt.Fprintf(sb, "    <!-- %v -->\n", strings.ReplaceAll(comment, "--", "- -"))
+	}
+}
+
+// the tags of inline markup, like <b>, </b>, <br/>, or <xliff:g id="count">
+var markupTagFinder = regexp.MustCompile(`<(/?)([A-Za-z_][\w:.-]*)(?:\s+[A-Za-z_][\w:.-]*\s*=\s*(?:"[^"<]*"|'[^'<]*'))*\s*(/?)>`)
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;")
+
+// escapeValue escapes the value like Escape does, but keeps well-formed inline markup as it is, the same as Read does,
+// so only the text around it gets xml escaped.
+func escapeValue(s string) string {
+	var sb strings.Builder
+	last := 0
+	for _, tag := range markupTags(s) {
+		sb.WriteString(textEscaper.Replace(escapeString(s[last:tag[0]], last == 0)))
+		sb.WriteString(s[tag[0]:tag[1]])
+		last = tag[1]
+	}
+	sb.WriteString(textEscaper.Replace(escapeString(s[last:], last == 0)))
+	return quoteIfNeeded(sb.String())
+}
+
+// markupTags finds the start and end of each tag, or nothing if the tags aren't well-formed,
+// in which case they are escaped as text
+func markupTags(s string) [][]int {
+	matches := markupTagFinder.FindAllStringSubmatchIndex(s, -1)
+	var open []string
+	for _, m := range matches {
+		isEnd, name, isEmpty := m[3] > m[2], s[m[4]:m[5]], m[7] > m[6]
+		switch {
+		case isEnd && (isEmpty || strings.Contains(s[m[5]:m[1]], "=")):
+			return nil
+		case isEnd:
+			if len(open) == 0 || open[len(open)-1] != name {
+				return nil
+			}
+			open = open[:len(open)-1]
+		case !isEmpty:
+			open = append(open, name)
+		}
+	}
+	if len(open) > 0 {
+		return nil
+	}
+	return matches
+}
+
+type resource struct {
+	name     string
+	comments []string
+
+	isPlural bool
+	value    string
+	// keyed by quantity
+	pluralValues map[string]string
+}
+
+// Read reads the default strings.xml, from the values directory, as the source strings, along with the strings.xml of the language, if any,
+// as the translations. Like Write, the resource name becomes the msgctxt and the source string the msgid.
+// For plurals, the one and other items of the source strings become the msgid and msgid_plural.
+// Strings marked translatable="false" are skipped, as are string arrays.
+func Read(source io.Reader, translations io.Reader, tag language.Tag) (gettext.Document, error) {
+	sourceResources, err := readResources(source)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+
+	translatedResources := make(map[string]resource)
+	if translations != nil {
+		resources, err := readResources(translations)
+		if err != nil {
+			return gettext.Document{}, err
+		}
+		for _, res := range resources {
+			translatedResources[res.name] = res
+		}
+	}
+
+	plurals, err := gettext.DefaultPluralMapping(tag)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+
+	entries := make([]gettext.Entry, 0, len(sourceResources))
+	for _, res := range sourceResources {
+		key := gettext.EntryKey{IsContextual: true, Context: res.name}
+		header := gettext.EntryHeader{ExtractedComments: res.comments}
+		translated, isTranslated := translatedResources[res.name]
+		if isTranslated && translated.isPlural != res.isPlural {
+			return gettext.Document{}, ConversionError{res.name, "The translation is not the same kind of resource as the source string."}
+		}
+
+		if !res.isPlural {
+			key.Id = res.value
+			entries = append(entries, gettext.NewEntry(key, header, translated.value, nil))
+			continue
+		}
+
+		key.IsPlural = true
+		key.PluralId = res.pluralValues["other"]
+		key.Id = res.pluralValues["one"]
+		if len(key.Id) == 0 {
+			key.Id = key.PluralId
+		}
+
+		pluralValues := plurals.PluralValues(func(pluralType gettext.PluralType) string {
+			return translated.pluralValues[pluralType.Category()]
+		})
+		entries = append(entries, gettext.NewEntry(key, header, "", pluralValues))
+	}
+
+	return gettext.NewDocument(tag, entries)
+}
+
+func readResources(r io.Reader) ([]resource, error) {
+	decoder := xml.NewDecoder(r)
+	var result []resource
+	var comments []string
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.Comment:
+			comments = append(comments, strings.TrimSpace(string(t)))
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "resources":
+				continue
+			case "string":
+				var element struct {
+					Name         string `xml:"name,attr"`
+					Translatable string `xml:"translatable,attr"`
+					Value        string `xml:",innerxml"`
+				}
+				if err := decoder.DecodeElement(&element, &t); err != nil {
+					return nil, err
+				}
+				if element.Translatable != "false" {
+					result = append(result, resource{name: element.Name, comments: comments, value: unescapeValue(innerText(element.Value))})
+				}
+			case "plurals":
+				var element struct {
+					Name  string `xml:"name,attr"`
+					Items []struct {
+						Quantity string `xml:"quantity,attr"`
+						Value    string `xml:",innerxml"`
+					} `xml:"item"`
+				}
+				if err := decoder.DecodeElement(&element, &t); err != nil {
+					return nil, err
+				}
+
+				res := resource{name: element.Name, comments: comments, isPlural: true, pluralValues: make(map[string]string)}
+				for _, item := range element.Items {
+					if _, ok := gettext.PluralTypeFromCategory(item.Quantity); !ok {
+						return nil, ConversionError{element.Name, fmt.Sprint("Unknown quantity: ", item.Quantity)}
+					}
+					res.pluralValues[item.Quantity] = unescapeValue(innerText(item.Value))
+				}
+				result = append(result, res)
+			default:
+				if err := decoder.Skip(); err != nil {
+					return nil, err
+				}
+			}
+			comments = nil
+		}
+	}
+}
+
+// innerText decodes the character data of the inner xml, keeping markup like <b> as it is
+func innerText(innerXml string) string {
+	var sb strings.Builder
+	decoder := xml.NewDecoder(strings.NewReader(innerXml))
+	decoder.Strict = false
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err != nil {
+			return sb.String()
+		}
+		if data, ok := token.(xml.CharData); ok {
+			sb.Write(data)
+		} else {
+			sb.WriteString(innerXml[offset:decoder.InputOffset()])
+		}
+	}
+}
+
+// Escape escapes a string the way android string resources need, without any xml escaping.
+// Values whose whitespace android would otherwise collapse are quoted.
+func Escape(s string) string {
+	return quoteIfNeeded(escapeString(s, true))
+}
+
+// escapeString escapes a part of a value, where atStart is whether the part starts the value
+func escapeString(s string, atStart bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\'':
+			sb.WriteString(`\'`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '@', '?':
+			// only special at the start, where they would reference other resources or attributes
+			if atStart && i == 0 {
+				sb.WriteRune('\\')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func quoteIfNeeded(escaped string) string {
+	if needsQuotes(escaped) {
+		return `"` + escaped + `"`
+	}
+	return escaped
+}
+
+func needsQuotes(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	first, last := rune(s[0]), rune(s[len(s)-1])
+	return unicode.IsSpace(first) || unicode.IsSpace(last) || strings.Contains(s, "  ")
+}
+
+// Unescape is the reverse of Escape, which is also how android reads string resources,
+// where whitespace outside of double quotes is collapsed into a single space.
+func Unescape(s string) string {
+	return unescape(s, false)
+}
+
+// unescapeValue unescapes the value like Unescape does, but keeps well-formed inline markup as it is
+func unescapeValue(s string) string {
+	return unescape(s, true)
+}
+
+func unescape(s string, keepMarkup bool) string {
+	var sb strings.Builder
+	inQuotes := false
+	pendingSpace := false
+	s = strings.TrimSpace(s)
+	var tags [][]int
+	if keepMarkup {
+		tags = markupTags(s)
+	}
+
+	for i := 0; i < len(s); {
+		for len(tags) > 0 && tags[0][0] < i {
+			tags = tags[1:]
+		}
+		if len(tags) > 0 && tags[0][0] == i {
+			if pendingSpace {
+				sb.WriteRune(' ')
+				pendingSpace = false
+			}
+			sb.WriteString(s[i:tags[0][1]])
+			i = tags[0][1]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if !inQuotes && unicode.IsSpace(r) {
+			pendingSpace = true
+			continue
+		}
+		if pendingSpace {
+			sb.WriteRune(' ')
+			pendingSpace = false
+		}
+
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == '\\' && i < len(s):
+			next, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+			switch next {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'u':
+				if i+4 <= len(s) {
+					if code, err := strconv.ParseUint(s[i:i+4], 16, 32); err == nil {
+						sb.WriteRune(rune(code))
+						i += 4
+						continue
+					}
+				}
+				sb.WriteRune(next)
+			default:
+				sb.WriteRune(next)
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,261 @@
+package android_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/android"
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+const documentText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Launchers cut it off -- keep it short
+msgctxt "app_name"
+msgid "Files"
+msgstr "Файлы"
+
+msgctxt "open_question"
+msgid "Open D'Artagnan's \"file\"?"
+msgstr "Открыть \"файл\" д'Артаньяна?"
+
+msgctxt "mention"
+msgid "@someone"
+msgstr "@кто-то"
+
+msgctxt "help"
+msgid "?Help"
+msgstr "?Справка"
+
+msgctxt "spaced"
+msgid " a  b "
+msgstr " а  б "
+
+msgctxt "files"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+
+#, fuzzy
+msgctxt "shared_by"
+msgid "Shared by @%1$s"
+msgstr "Поделился @%1$s"
+
+msgctxt "two_lines"
+msgid "First line\nSecond line"
+msgstr ""`
+
+const expectedXml = `<?xml version="1.0" encoding="UTF-8"?>
+<resources>
+    <!-- Launchers cut it off - - keep it short -->
+    <string name="app_name">Файлы</string>
+    <string name="open_question">Открыть \"файл\" д\'Артаньяна?</string>
+    <string name="mention">\@кто-то</string>
+    <string name="help">\?Справка</string>
+    <string name="spaced">" а  б "</string>
+    <plurals name="files">
+        <item quantity="one">%d файл</item>
+        <item quantity="few">%d файла</item>
+        <item quantity="many">%d файлов</item>
+        <item quantity="other">%d файлов</item>
+    </plurals>
+</resources>
+`
+
+func TestWrite(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := android.Write(&buf, &doc, android.Options{}); err != nil {
+		t.Fatal("Error writing xml: ", err)
+	}
+	if buf.String() != expectedXml {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedXml, buf.String())
+	}
+
+	buf.Reset()
+	if err := android.Write(&buf, &doc, android.Options{IncludeFuzzy: true}); err != nil {
+		t.Fatal("Error writing xml: ", err)
+	}
+	// @ is only escaped at the start, where it would reference another resource
+	if s := `<string name="shared_by">Поделился @%1$s</string>`; !strings.Contains(buf.String(), s) {
+		t.Errorf("Expected %v in:\n%v", s, buf.String())
+	}
+}
+
+func TestWrite_ReturnsError_WhenNameInvalid(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"
+
+msgid "Not a name"
+msgstr "Не имя"`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	err = android.Write(&bytes.Buffer{}, &doc, android.Options{})
+	if _, ok := err.(android.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", android.ConversionError{}, err, err)
+	}
+}
+
+const sourceXml = `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <!-- Launcher label -->
+    <string name="app_name">Files</string>
+    <string name="package" translatable="false">com.example.files</string>
+    <string name="open_question">Open D\'Artagnan\'s "file"?</string>
+    <string name="help">\?Help</string>
+    <string name="bold">Open <b>now</b> &amp; later</string>
+    <string-array name="sizes">
+        <item>Small</item>
+    </string-array>
+    <plurals name="files">
+        <item quantity="one">%d file</item>
+        <item quantity="other">%d files</item>
+    </plurals>
+</resources>`
+
+const translationXml = `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="app_name">Файлы</string>
+    <string name="open_question">"Открыть \"файл\"   д'Артаньяна?"</string>
+    <string name="help">\?Справка</string>
+    <plurals name="files">
+        <item quantity="one">%d файл</item>
+        <item quantity="few">%d файла</item>
+        <item quantity="many">%d файлов</item>
+        <item quantity="other">%d файла</item>
+    </plurals>
+</resources>`
+
+func TestRead(t *testing.T) {
+	doc, err := android.Read(strings.NewReader(sourceXml), strings.NewReader(translationXml), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading xml: ", err)
+	}
+
+	// the translated other item has nowhere to go, since ngettext never picks it
+	expected := `
+#. Launcher label
+msgctxt "app_name"
+msgid "Files"
+msgstr "Файлы"
+
+msgctxt "open_question"
+msgid "Open D'Artagnan's file?"
+msgstr "Открыть \"файл\"   д'Артаньяна?"
+
+msgctxt "help"
+msgid "?Help"
+msgstr "?Справка"
+
+msgctxt "bold"
+msgid "Open <b>now</b> & later"
+msgstr ""
+
+msgctxt "files"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+`
+	var buf bytes.Buffer
+	for _, entry := range doc.Entries[1:] {
+		for _, line := range entry.Lines {
+			buf.WriteString(line.RawLine)
+			buf.WriteString("\n")
+		}
+	}
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buf.String())
+	}
+	if doc.Header.Tag != language.Russian {
+		t.Errorf("Expected language %v but got %v", language.Russian, doc.Header.Tag)
+	}
+}
+
+func TestRead_ReturnsError_WhenKindsDiffer(t *testing.T) {
+	translations := `<resources><string name="files">Файлы</string></resources>`
+	_, err := android.Read(strings.NewReader(sourceXml), strings.NewReader(translations), language.Russian)
+	if _, ok := err.(android.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", android.ConversionError{}, err, err)
+	}
+}
+
+func TestValuesDirectory(t *testing.T) {
+	for tag, expected := range map[string]string{
+		"ru":         "values-ru",
+		"pt-BR":      "values-pt-rBR",
+		"sr-Latn":    "values-b+sr+Latn",
+		"zh-Hant-TW": "values-b+zh+Hant+TW",
+	} {
+		if actual := android.ValuesDirectory(language.MustParse(tag)); actual != expected {
+			t.Errorf("Expected %v for %v but got %v", expected, tag, actual)
+		}
+	}
+}
+
+func TestWrite_KeepsInlineMarkup(t *testing.T) {
+	source := `<resources>
+    <string name="bold">Open <b>now</b> &amp; later</string>
+    <string name="placeholder">Found <xliff:g id="count">%d</xliff:g> files</string>
+</resources>`
+	doc, err := android.Read(strings.NewReader(source), strings.NewReader(source), language.English)
+	if err != nil {
+		t.Fatal("Error reading xml: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := android.Write(&buf, &doc, android.Options{}); err != nil {
+		t.Fatal("Error writing xml: ", err)
+	}
+	for _, s := range []string{
+		`<string name="bold">Open <b>now</b> &amp; later</string>`,
+		`<string name="placeholder">Found <xliff:g id="count">%d</xliff:g> files</string>`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected %v in:\n%v", s, buf.String())
+		}
+	}
+}
+
+func TestWrite_EscapesTextThatIsNotMarkup(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: en\n"
+
+msgctxt "compare"
+msgid "a < b & c"
+msgstr "a < b & c"
+
+msgctxt "unclosed"
+msgid "<b>bold"
+msgstr "<b>bold"`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := android.Write(&buf, &doc, android.Options{}); err != nil {
+		t.Fatal("Error writing xml: ", err)
+	}
+	for _, s := range []string{
+		`<string name="compare">a &lt; b &amp; c</string>`,
+		`<string name="unclosed">&lt;b>bold</string>`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected %v in:\n%v", s, buf.String())
+		}
+	}
+}
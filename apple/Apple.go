@@ -0,0 +1,78 @@
+package apple
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+type Options struct {
+	// UTF16 writes .strings files as UTF-16 with a BOM, which older versions of Xcode expect, instead of UTF-8.
+	UTF16 bool
+
+	// IncludeFuzzy also writes fuzzy translations.
+	IncludeFuzzy bool
+}
+
+type ConversionError struct {
+	Key             string
+	Reason          string
+	UnderlyingError error
+}
+
+func (e ConversionError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to convert '%v': %v Underlying error: %v", e.Key, e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to convert '%v': %v", e.Key, e.Reason)
+}
+
+// the comment Xcode adds when a string has no comment, which isn't worth keeping
+const noComment = "No comment provided by engineer."
+
+// entryKey is the key of an entry in .strings and .stringsdict files, which is the msgctxt for apps with identifiers as keys,
+// or the msgid for apps with source strings as keys.
+func entryKey(entry *gettext.Entry) string {
+	if entry.IsContextual {
+		return entry.Context
+	}
+	return entry.Id
+}
+
+func isWritten(entry *gettext.Entry, options Options) bool {
+	return len(entry.Id) > 0 && !entry.IsObsolete && (!entry.Header.IsFuzzy() || options.IncludeFuzzy)
+}
+
+// keyFor is the reverse of entryKey, where the source string is only known when a source file,
+// usually from the development language's lproj, has been read
+func keyFor(key string, sourceValue string, hasSource bool) gettext.EntryKey {
+	if !hasSource || key == sourceValue {
+		return gettext.EntryKey{Id: key}
+	}
+	return gettext.EntryKey{IsContextual: true, Context: key, Id: sourceValue}
+}
+
+// readAll reads UTF-8 or, based on the BOM, UTF-16
+func readAll(r io.Reader) (string, error) {
+	data, err := io.ReadAll(transform.NewReader(r, unicode.BOMOverride(unicode.UTF8.NewDecoder())))
+	if err != nil {
+		return "", ConversionError{Reason: "Unable to decode the file.", UnderlyingError: err}
+	}
+	return string(data), nil
+}
+
+func writeAll(w io.Writer, s string, utf16 bool) error {
+	if !utf16 {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+
+	tw := transform.NewWriter(w, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder())
+	if _, err := io.WriteString(tw, s); err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,277 @@
+package apple
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+// WriteStrings writes the non-plural translations of the document as a Localizable.strings file,
+// with extracted comments as the comments of each string. Plural entries go in a .stringsdict file instead.
+// Untranslated and obsolete entries are skipped, so that the development language is used for them.
+func WriteStrings(w io.Writer, d *gettext.Document, options Options) error {
+	var sb strings.Builder
+
+	for _, entry := range d.Entries {
+		if !isWritten(&entry, options) || entry.IsPlural || len(entry.Value) == 0 {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		if len(entry.Header.ExtractedComments) > 0 {
+			// */ would end the comment early
+			comment := strings.Join(entry.Header.ExtractedComments, "\n")
+			fmt.Fprintf(&sb, "/* %v */\n", strings.ReplaceAll(comment, "*/", "* /"))
+		}
+		fmt.Fprintf(&sb, "%v = %v;\n", quote(entryKey(&entry)), quote(entry.Value))
+	}
+
+	return writeAll(w, sb.String(), options.UTF16)
+}
+
+type stringsEntry struct {
+	key      string
+	value    string
+	comments []string
+}
+
+// ReadStrings reads a .strings file of translations, along with the .strings file of the development language as the source strings,
+// which may be nil for apps that use source strings as keys. With a source file, keys other than the source string become the msgctxt.
+// Either way, comments become extracted comments.
+func ReadStrings(source io.Reader, translations io.Reader, tag language.Tag) (gettext.Document, error) {
+	var translatedEntries []stringsEntry
+	if translations != nil {
+		var err error
+		if translatedEntries, err = readStringsEntries(translations); err != nil {
+			return gettext.Document{}, err
+		}
+	}
+
+	if source == nil {
+		entries := make([]gettext.Entry, 0, len(translatedEntries))
+		for _, e := range translatedEntries {
+			entries = append(entries, gettext.NewEntry(keyFor(e.key, "", false), gettext.EntryHeader{ExtractedComments: e.comments}, e.value, nil))
+		}
+		return gettext.NewDocument(tag, entries)
+	}
+
+	sourceEntries, err := readStringsEntries(source)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+	translatedValues := make(map[string]string, len(translatedEntries))
+	for _, e := range translatedEntries {
+		translatedValues[e.key] = e.value
+	}
+
+	entries := make([]gettext.Entry, 0, len(sourceEntries))
+	for _, e := range sourceEntries {
+		key := keyFor(e.key, e.value, true)
+		entries = append(entries, gettext.NewEntry(key, gettext.EntryHeader{ExtractedComments: e.comments}, translatedValues[e.key], nil))
+	}
+	return gettext.NewDocument(tag, entries)
+}
+
+func readStringsEntries(r io.Reader) ([]stringsEntry, error) {
+	s, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+	// in case a UTF-8 BOM is left by the decoder
+	p := stringsParser{s: strings.TrimPrefix(s, "\uFEFF")}
+	return p.parse()
+}
+
+type stringsParser struct {
+	s   string
+	pos int
+}
+
+func (p *stringsParser) parse() ([]stringsEntry, error) {
+	var result []stringsEntry
+	for {
+		comments, err := p.skipSpaceAndComments()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.s) {
+			return result, nil
+		}
+
+		key, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		if _, err := p.skipSpaceAndComments(); err != nil {
+			return nil, err
+		}
+		value, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+
+		result = append(result, stringsEntry{key, value, comments})
+	}
+}
+
+// skipSpaceAndComments returns the lines of the last comment, which is the one for the string that follows
+func (p *stringsParser) skipSpaceAndComments() ([]string, error) {
+	var comment string
+	hasComment := false
+	for p.pos < len(p.s) {
+		rest := p.s[p.pos:]
+		switch {
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return nil, p.error("Unterminated comment.")
+			}
+			comment, hasComment = rest[2:2+end], true
+			p.pos += end + 4
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			comment, hasComment = rest[2:end], true
+			p.pos += end
+		case strings.ContainsRune(" \t\r\n", rune(rest[0])):
+			p.pos++
+		default:
+			return commentLines(comment, hasComment), nil
+		}
+	}
+	return nil, nil
+}
+
+func commentLines(comment string, hasComment bool) []string {
+	comment = strings.TrimSpace(comment)
+	if !hasComment || len(comment) == 0 || comment == noComment {
+		return nil
+	}
+
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return lines
+}
+
+func (p *stringsParser) expect(c byte) error {
+	if _, err := p.skipSpaceAndComments(); err != nil {
+		return err
+	}
+	if p.pos >= len(p.s) || p.s[p.pos] != c {
+		return p.error(fmt.Sprintf("Expected '%c'.", c))
+	}
+	p.pos++
+	return nil
+}
+
+func (p *stringsParser) readString() (string, error) {
+	if p.pos >= len(p.s) {
+		return "", p.error("Expected a string.")
+	}
+	if p.s[p.pos] != '"' {
+		// unquoted strings, as old-style plists allow
+		start := p.pos
+		for p.pos < len(p.s) && isUnquotedChar(p.s[p.pos]) {
+			p.pos++
+		}
+		if start == p.pos {
+			return "", p.error("Expected a string.")
+		}
+		return p.s[start:p.pos], nil
+	}
+
+	var sb strings.Builder
+	p.pos++
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if err := p.readEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.error("Unterminated string.")
+}
+
+func (p *stringsParser) readEscape(sb *strings.Builder) error {
+	if p.pos >= len(p.s) {
+		return p.error("Unterminated string.")
+	}
+
+	c := p.s[p.pos]
+	p.pos++
+	switch c {
+	case 'n':
+		sb.WriteByte('\n')
+	case 't':
+		sb.WriteByte('\t')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'a':
+		sb.WriteByte('\a')
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'v':
+		sb.WriteByte('\v')
+	case 'U', 'u':
+		if p.pos+4 > len(p.s) {
+			return p.error("Invalid unicode escape.")
+		}
+		code, err := strconv.ParseUint(p.s[p.pos:p.pos+4], 16, 32)
+		if err != nil {
+			return p.error("Invalid unicode escape.")
+		}
+		sb.WriteRune(rune(code))
+		p.pos += 4
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		end := p.pos - 1
+		for end < len(p.s) && end < p.pos+2 && p.s[end] >= '0' && p.s[end] <= '7' {
+			end++
+		}
+		code, _ := strconv.ParseUint(p.s[p.pos-1:end], 8, 32)
+		sb.WriteRune(rune(code))
+		p.pos = end
+	default:
+		sb.WriteByte(c)
+	}
+	return nil
+}
+
+func isUnquotedChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_$:./-", c) >= 0
+}
+
+func (p *stringsParser) error(reason string) error {
+	line := strings.Count(p.s[:min(p.pos, len(p.s))], "\n") + 1
+	return ConversionError{Reason: fmt.Sprintf("%v Line: %v", reason, line)}
+}
+
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func quote(s string) string {
+	return `"` + quoteEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,257 @@
+package apple
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+const plistHeader = xml.Header + `<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+const (
+	formatKey       = "NSStringLocalizedFormatKey"
+	specTypeKey     = "NSStringFormatSpecTypeKey"
+	valueTypeKey    = "NSStringFormatValueTypeKey"
+	pluralRuleType  = "NSStringPluralRuleType"
+	writtenVariable = "value"
+)
+
+var (
+	variableFinder = regexp.MustCompile(`%(?:\d+\$)?#@([^@]+)@`)
+	// the length modifier and conversion of numeric format specifiers, like "ld" in "%ld"
+	valueTypeFinder = regexp.MustCompile(`%(?:\d+\$)?[-+ 0#']*\d*(?:\.\d+)?((?:hh|h|ll|l|q|z|t|j)?[diouxXeEfFgGaA])`)
+)
+
+// WriteStringsdict writes the plural translations of the document as a Localizable.stringsdict file,
+// with a string per plural category of the document. The value type of the format is based on the first numeric format specifier
+// of the other form, such as "ld" for "%ld files", and is "d" if there are none.
+// Untranslated and obsolete entries are skipped, and a plural entry is only written once all of its msgstr[n] are,
+// so that iOS shows the development language instead of an empty form.
+func WriteStringsdict(w io.Writer, d *gettext.Document, options Options) error {
+	plurals := sync.OnceValues(d.Header.PluralMapping)
+	var sb strings.Builder
+	sb.WriteString(plistHeader)
+	sb.WriteString("<dict>\n")
+
+	for _, entry := range d.Entries {
+		if !isWritten(&entry, options) || !entry.IsPlural {
+			continue
+		}
+
+		plurals, err := plurals()
+		if err != nil {
+			return err
+		}
+		if err := plurals.ValidatePluralCount(&entry); err != nil {
+			return err
+		}
+		if !entry.IsTranslated() {
+			continue
+		}
+
+		valueType := "d"
+		if match := valueTypeFinder.FindStringSubmatch(plurals.Value(entry.PluralValues, gettext.PluralTypeOther)); match != nil {
+			valueType = match[1]
+		}
+
+		writePlistString(&sb, 1, "key", entryKey(&entry))
+		sb.WriteString("\t<dict>\n")
+		writePlistString(&sb, 2, "key", formatKey)
+		writePlistString(&sb, 2, "string", fmt.Sprintf("%%#@%v@", writtenVariable))
+		writePlistString(&sb, 2, "key", writtenVariable)
+		sb.WriteString("\t\t<dict>\n")
+		writePlistString(&sb, 3, "key", specTypeKey)
+		writePlistString(&sb, 3, "string", pluralRuleType)
+		writePlistString(&sb, 3, "key", valueTypeKey)
+		writePlistString(&sb, 3, "string", valueType)
+		for _, pluralType := range plurals.Categories() {
+			writePlistString(&sb, 3, "key", pluralType.Category())
+			writePlistString(&sb, 3, "string", plurals.Value(entry.PluralValues, pluralType))
+		}
+		sb.WriteString("\t\t</dict>\n")
+		sb.WriteString("\t</dict>\n")
+	}
+
+	sb.WriteString("</dict>\n</plist>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func writePlistString(sb *strings.Builder, depth int, element string, value string) {
+	fmt.Fprintf(sb, "%v<%v>%v</%v>\n", strings.Repeat("\t", depth), element, textEscaper.Replace(value), element)
+}
+
+type stringsdictEntry struct {
+	key string
+	// keyed by category, with the rest of the format key already filled in
+	values map[string]string
+}
+
+// ReadStringsdict reads a .stringsdict file of translations, along with the .stringsdict file of the development language as the source strings,
+// which, like with ReadStrings, may be nil for apps that use source strings as keys.
+// The one and other forms of the source strings become the msgid and msgid_plural.
+// Format keys may have text around the variable, like "%#@files@ left", but only one variable is supported.
+func ReadStringsdict(source io.Reader, translations io.Reader, tag language.Tag) (gettext.Document, error) {
+	var translatedEntries []stringsdictEntry
+	if translations != nil {
+		var err error
+		if translatedEntries, err = readStringsdictEntries(translations); err != nil {
+			return gettext.Document{}, err
+		}
+	}
+
+	plurals, err := gettext.DefaultPluralMapping(tag)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+	pluralValues := func(values map[string]string) []string {
+		return plurals.PluralValues(func(pluralType gettext.PluralType) string { return values[pluralType.Category()] })
+	}
+
+	if source == nil {
+		entries := make([]gettext.Entry, 0, len(translatedEntries))
+		for _, e := range translatedEntries {
+			key := keyFor(e.key, "", false)
+			key.IsPlural = true
+			key.PluralId = e.key
+			entries = append(entries, gettext.NewEntry(key, gettext.EntryHeader{}, "", pluralValues(e.values)))
+		}
+		return gettext.NewDocument(tag, entries)
+	}
+
+	sourceEntries, err := readStringsdictEntries(source)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+	translatedValues := make(map[string]map[string]string, len(translatedEntries))
+	for _, e := range translatedEntries {
+		translatedValues[e.key] = e.values
+	}
+
+	entries := make([]gettext.Entry, 0, len(sourceEntries))
+	for _, e := range sourceEntries {
+		id, pluralId := e.values["one"], e.values["other"]
+		if len(id) == 0 {
+			id = pluralId
+		}
+		key := keyFor(e.key, id, true)
+		key.IsPlural = true
+		key.PluralId = pluralId
+		entries = append(entries, gettext.NewEntry(key, gettext.EntryHeader{}, "", pluralValues(translatedValues[e.key])))
+	}
+	return gettext.NewDocument(tag, entries)
+}
+
+func readStringsdictEntries(r io.Reader) ([]stringsdictEntry, error) {
+	root, err := readPlist(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]stringsdictEntry, 0, len(root.keys))
+	for _, key := range root.keys {
+		dict, ok := root.values[key].(*plistDict)
+		if !ok {
+			return nil, ConversionError{Key: key, Reason: "Expected a dict."}
+		}
+
+		format, _ := dict.values[formatKey].(string)
+		variables := variableFinder.FindAllStringSubmatch(format, -1)
+		if len(variables) != 1 {
+			return nil, ConversionError{Key: key, Reason: fmt.Sprintf("Expected a %v with exactly one variable. Found: %v", formatKey, format)}
+		}
+		variable, ok := dict.values[variables[0][1]].(*plistDict)
+		if !ok {
+			return nil, ConversionError{Key: key, Reason: fmt.Sprint("Unable to find the dict of variable ", variables[0][1])}
+		}
+
+		entry := stringsdictEntry{key: key, values: make(map[string]string)}
+		for _, category := range variable.keys {
+			value, ok := variable.values[category].(string)
+			if _, isCategory := gettext.PluralTypeFromCategory(category); !ok || !isCategory {
+				continue
+			}
+			entry.values[category] = strings.Replace(format, variables[0][0], value, 1)
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// plistDict keeps the order of keys, with values that are either strings or dicts. Other types of values are nil.
+type plistDict struct {
+	keys   []string
+	values map[string]any
+}
+
+func readPlist(r io.Reader) (*plistDict, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, ConversionError{Reason: "Unable to find the root dict of the plist.", UnderlyingError: err}
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "dict" {
+			return readPlistDict(decoder)
+		}
+	}
+}
+
+func readPlistDict(decoder *xml.Decoder) (*plistDict, error) {
+	dict := &plistDict{values: make(map[string]any)}
+	var key string
+	hasKey := false
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, ConversionError{Reason: "Unable to read the plist.", UnderlyingError: err}
+		}
+
+		switch t := token.(type) {
+		case xml.EndElement:
+			return dict, nil
+		case xml.StartElement:
+			var value any
+			switch t.Name.Local {
+			case "key":
+				if err := decoder.DecodeElement(&key, &t); err != nil {
+					return nil, err
+				}
+				hasKey = true
+				continue
+			case "string":
+				var s string
+				if err := decoder.DecodeElement(&s, &t); err != nil {
+					return nil, err
+				}
+				value = s
+			case "dict":
+				if value, err = readPlistDict(decoder); err != nil {
+					return nil, err
+				}
+			default:
+				if err := decoder.Skip(); err != nil {
+					return nil, err
+				}
+			}
+
+			if !hasKey {
+				return nil, ConversionError{Reason: fmt.Sprint("Found a value without a key: ", t.Name.Local)}
+			}
+			dict.keys = append(dict.keys, key)
+			dict.values[key] = value
+			hasKey = false
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package apple_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/apple"
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/language"
+	"golang.org/x/text/transform"
+)
+
+const documentText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Keys like /*this*/ end comments early
+msgctxt "app_name"
+msgid "Files"
+msgstr "Файлы"
+
+msgid "Say \"hi\"\n"
+msgstr "Скажи \"привет\"\n"
+
+msgctxt "files"
+msgid "%ld file"
+msgid_plural "%ld files"
+msgstr[0] "%ld файл"
+msgstr[1] "%ld файла"
+msgstr[2] "%ld файлов"
+
+#, fuzzy
+msgid "Copy “%@”"
+msgstr "Копировать «%@»"
+
+msgid "Move to Trash"
+msgstr ""`
+
+const expectedStrings = `/* Keys like /*this* / end comments early */
+"app_name" = "Файлы";
+
+"Say \"hi\"\n" = "Скажи \"привет\"\n";
+`
+
+const expectedStringsdict = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@value@</string>
+		<key>value</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>ld</string>
+			<key>one</key>
+			<string>%ld файл</string>
+			<key>few</key>
+			<string>%ld файла</string>
+			<key>many</key>
+			<string>%ld файлов</string>
+			<key>other</key>
+			<string>%ld файлов</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+func entriesText(doc *gettext.Document) string {
+	var buf bytes.Buffer
+	for _, entry := range doc.Entries[1:] {
+		for _, line := range entry.Lines {
+			buf.WriteString(line.RawLine)
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
+
+func TestWriteStrings(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := apple.WriteStrings(&buf, &doc, apple.Options{}); err != nil {
+		t.Fatal("Error writing strings: ", err)
+	}
+	if buf.String() != expectedStrings {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedStrings, buf.String())
+	}
+
+	buf.Reset()
+	if err := apple.WriteStrings(&buf, &doc, apple.Options{UTF16: true, IncludeFuzzy: true}); err != nil {
+		t.Fatal("Error writing strings: ", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0xff, 0xfe}) {
+		t.Errorf("Expected a UTF-16 little endian BOM but got: %v", buf.Bytes()[:2])
+	}
+
+	// and read back, which also checks the fuzzy entry got written
+	read, err := apple.ReadStrings(nil, &buf, language.Russian)
+	if err != nil {
+		t.Fatal("Error reading strings: ", err)
+	}
+	if len(read.Entries) != 4 || read.Entries[3].Id != "Copy “%@”" || read.Entries[3].Value != "Копировать «%@»" {
+		t.Errorf("Expected the fuzzy entry to be written and read back, but got:\n%v", entriesText(&read))
+	}
+}
+
+func TestWriteStringsdict(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := apple.WriteStringsdict(&buf, &doc, apple.Options{}); err != nil {
+		t.Fatal("Error writing stringsdict: ", err)
+	}
+	if buf.String() != expectedStringsdict {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedStringsdict, buf.String())
+	}
+}
+
+const sourceStrings = `/* Title of the main window,
+   which Finder also shows */
+"app_name" = "Files";
+
+/* No comment provided by engineer. */
+"Hello" = "Hello";
+
+// Shown when saving
+save_button = "Save\U2026";
+`
+
+const translationStrings = `"app_name" = "Файлы";
+"Hello" = "Привет";
+`
+
+func TestReadStrings(t *testing.T) {
+	doc, err := apple.ReadStrings(strings.NewReader(sourceStrings), strings.NewReader(translationStrings), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading strings: ", err)
+	}
+
+	expected := `
+#. Title of the main window,
+#. which Finder also shows
+msgctxt "app_name"
+msgid "Files"
+msgstr "Файлы"
+
+msgid "Hello"
+msgstr "Привет"
+
+#. Shown when saving
+msgctxt "save_button"
+msgid "Save…"
+msgstr ""
+`
+	if actual := entriesText(&doc); actual != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, actual)
+	}
+}
+
+func TestReadStrings_DecodesUTF16AndOctalEscapes(t *testing.T) {
+	var translations bytes.Buffer
+	w := transform.NewWriter(&translations, unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewEncoder())
+	if _, err := io.WriteString(w, `"greeting" = "Скажи \042привет\042";`); err != nil {
+		t.Fatal("Error encoding strings: ", err)
+	}
+	w.Close()
+
+	doc, err := apple.ReadStrings(nil, &translations, language.Russian)
+	if err != nil {
+		t.Fatal("Error reading strings: ", err)
+	}
+	if entry := doc.Entries[1]; entry.Id != "greeting" || entry.Value != `Скажи "привет"` {
+		t.Errorf("Expected the octal escapes to be quotes, but got:\n%v", entriesText(&doc))
+	}
+}
+
+func TestReadStrings_ReturnsError_WhenMalformed(t *testing.T) {
+	_, err := apple.ReadStrings(nil, strings.NewReader(`"key" = "value"`), language.Russian)
+	if _, ok := err.(apple.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", apple.ConversionError{}, err, err)
+	}
+}
+
+const sourceStringsdict = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>files_left</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@files@ left</string>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d file</string>
+			<key>other</key>
+			<string>%d files</string>
+		</dict>
+	</dict>
+</dict>
+</plist>`
+
+const translationStringsdict = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>files_left</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>Осталось %#@files@</string>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>zero</key>
+			<string>ничего</string>
+			<key>one</key>
+			<string>%d файл</string>
+			<key>few</key>
+			<string>%d файла</string>
+			<key>many</key>
+			<string>%d файлов</string>
+			<key>other</key>
+			<string>%d файла</string>
+		</dict>
+	</dict>
+</dict>
+</plist>`
+
+func TestReadStringsdict(t *testing.T) {
+	doc, err := apple.ReadStringsdict(strings.NewReader(sourceStringsdict), strings.NewReader(translationStringsdict), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading stringsdict: ", err)
+	}
+
+	expected := `
+msgctxt "files_left"
+msgid "%d file left"
+msgid_plural "%d files left"
+msgstr[0] "Осталось %d файл"
+msgstr[1] "Осталось %d файла"
+msgstr[2] "Осталось %d файлов"
+`
+	if actual := entriesText(&doc); actual != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, actual)
+	}
+}
+
+func TestReadStringsdict_RoundTrips(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := apple.WriteStringsdict(&buf, &doc, apple.Options{}); err != nil {
+		t.Fatal("Error writing stringsdict: ", err)
+	}
+	read, err := apple.ReadStringsdict(nil, &buf, language.Russian)
+	if err != nil {
+		t.Fatal("Error reading stringsdict: ", err)
+	}
+
+	if len(read.Entries) != 2 || read.Entries[1].Id != "files" || read.Entries[1].PluralValues[2] != "%ld файлов" {
+		t.Errorf("Expected the plural entry to be read back, but got:\n%v", entriesText(&read))
+	}
+}
+
+func TestReadStringsdict_ReturnsError_WhenMultipleVariables(t *testing.T) {
+	translations := `<plist version="1.0"><dict><key>k</key><dict>
+		<key>NSStringLocalizedFormatKey</key><string>%#@a@ %#@b@</string>
+	</dict></dict></plist>`
+	_, err := apple.ReadStringsdict(nil, strings.NewReader(translations), language.Russian)
+	if _, ok := err.(apple.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", apple.ConversionError{}, err, err)
+	}
+}
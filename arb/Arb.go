@@ -0,0 +1,416 @@
+package arb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+// what arb metadata has that po files don't is kept in extracted comments with these prefixes,
+// so that converting a document back gives the same metadata
+const (
+	placeholdersCommentPrefix = "arb-placeholders: "
+	pluralArgCommentPrefix    = "arb-plural-arg: "
+)
+
+const (
+	localeKey        = "@@locale"
+	defaultPluralArg = "count"
+)
+
+// flutter generates a method per message, so keys need to be valid identifiers
+var keyValidator = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+type Options struct {
+	// IncludeFuzzy writes fuzzy entries too.
+	IncludeFuzzy bool
+}
+
+type ConversionError struct {
+	Key             string
+	Reason          string
+	UnderlyingError error
+}
+
+func (e ConversionError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to convert '%v': %v Underlying error: %v", e.Key, e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to convert '%v': %v", e.Key, e.Reason)
+}
+
+type metadata struct {
+	Description  string          `json:"description,omitempty"`
+	Placeholders json.RawMessage `json:"placeholders,omitempty"`
+}
+
+// Write writes the translations of the document as an arb file, where the msgctxt is the key, or the msgid for entries without one.
+// Extracted comments become the description of the @key metadata, and plural entries become ICU plural messages,
+// like "{count, plural, one{{count} file} other{{count} files}}", with a case per plural category of the document.
+// Untranslated and obsolete entries are left out, as are plural entries with any msgstr[n] untranslated,
+// so that flutter falls back to the template arb for them.
+func Write(w io.Writer, d *gettext.Document, options Options) error {
+	plurals := sync.OnceValues(d.Header.PluralMapping)
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	writeMember(&buf, localeKey, strings.ReplaceAll(d.Header.Tag.String(), "-", "_"), false)
+
+	for _, entry := range d.Entries {
+		if len(entry.Id) == 0 || entry.IsObsolete || (entry.Header.IsFuzzy() && !options.IncludeFuzzy) {
+			continue
+		}
+
+		key := entry.Id
+		if entry.IsContextual {
+			key = entry.Context
+		}
+		if !keyValidator.MatchString(key) {
+			return ConversionError{Key: key, Reason: "Not a valid key. Entries should have the key as the msgctxt."}
+		}
+
+		meta, pluralArg, err := createMetadata(key, &entry.Header)
+		if err != nil {
+			return err
+		}
+
+		value := entry.Value
+		if entry.IsPlural {
+			plurals, err := plurals()
+			if err != nil {
+				return err
+			}
+			if err := plurals.ValidatePluralCount(&entry); err != nil {
+				return err
+			}
+			if !entry.IsTranslated() {
+				continue
+			}
+
+			value = pluralMessage(pluralArg, &plurals, entry.PluralValues)
+			if meta.Placeholders == nil {
+				meta.Placeholders = json.RawMessage(fmt.Sprintf(`{%q: {"type": "int"}}`, pluralArg))
+			}
+		}
+		if len(value) == 0 {
+			continue
+		}
+
+		writeMember(&buf, key, value, true)
+		if meta.Description != "" || meta.Placeholders != nil {
+			writeMember(&buf, "@"+key, meta, true)
+		}
+	}
+
+	buf.WriteString("\n}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func createMetadata(key string, header *gettext.EntryHeader) (metadata, string, error) {
+	var meta metadata
+	pluralArg := defaultPluralArg
+	var description []string
+
+	for _, comment := range header.ExtractedComments {
+		switch {
+		case strings.HasPrefix(comment, placeholdersCommentPrefix):
+			placeholders := json.RawMessage(strings.TrimPrefix(comment, placeholdersCommentPrefix))
+			if !json.Valid(placeholders) {
+				return metadata{}, "", ConversionError{Key: key, Reason: fmt.Sprint("Invalid placeholders: ", comment)}
+			}
+			meta.Placeholders = placeholders
+		case strings.HasPrefix(comment, pluralArgCommentPrefix):
+			pluralArg = strings.TrimPrefix(comment, pluralArgCommentPrefix)
+		default:
+			description = append(description, comment)
+		}
+	}
+
+	meta.Description = strings.Join(description, "\n")
+	return meta, pluralArg, nil
+}
+
+// writeMember writes a member of the root object, with the same two space indentation as other arb tools
+func writeMember(buf *bytes.Buffer, key string, value any, hasPrevious bool) {
+	if hasPrevious {
+		buf.WriteString(",\n")
+	}
+
+	// keeps <b> as it is, rather than \u003cb\u003e
+	var encoded bytes.Buffer
+	e := json.NewEncoder(&encoded)
+	e.SetEscapeHTML(false)
+	e.SetIndent("  ", "  ")
+	// strings and metadata always encode
+	_ = e.Encode(key)
+	buf.WriteString("  ")
+	buf.Write(bytes.TrimSpace(encoded.Bytes()))
+	buf.WriteString(": ")
+
+	encoded.Reset()
+	_ = e.Encode(value)
+	buf.Write(bytes.TrimSpace(encoded.Bytes()))
+}
+
+func pluralMessage(arg string, plurals *gettext.PluralMapping, values []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "{%v, plural,", arg)
+	for _, pluralType := range plurals.Categories() {
+		fmt.Fprintf(&sb, " %v{%v}", pluralType.Category(), plurals.Value(values, pluralType))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+type resource struct {
+	key   string
+	value string
+	meta  *metadata
+
+	isPlural     bool
+	pluralArg    string
+	pluralValues map[string]string
+}
+
+// Read reads an arb file of translations, along with the template arb file as the source strings, which may be nil for apps that use source strings as keys.
+// With a source file, keys become the msgctxt and the source strings the msgid, and the one and other cases of plural messages become the msgid and msgid_plural.
+// Messages that are a plural and nothing else become plural entries. Other messages, like ones with =0 cases or text around the plural,
+// are kept as they are, since po files have no place for them. Descriptions become extracted comments, along with placeholders, which are kept as json.
+func Read(source io.Reader, translations io.Reader, tag language.Tag) (gettext.Document, error) {
+	var translatedResources []resource
+	if translations != nil {
+		var err error
+		if translatedResources, err = readResources(translations); err != nil {
+			return gettext.Document{}, err
+		}
+	}
+
+	plurals, err := gettext.DefaultPluralMapping(tag)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+
+	if source == nil {
+		entries := make([]gettext.Entry, 0, len(translatedResources))
+		for _, res := range translatedResources {
+			key := gettext.EntryKey{Id: res.key}
+			entries = append(entries, createEntry(key, &res, &res, res.meta, &plurals))
+		}
+		return gettext.NewDocument(tag, entries)
+	}
+
+	sourceResources, err := readResources(source)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+	translatedByKey := make(map[string]*resource, len(translatedResources))
+	for i := range translatedResources {
+		translatedByKey[translatedResources[i].key] = &translatedResources[i]
+	}
+
+	entries := make([]gettext.Entry, 0, len(sourceResources))
+	for _, res := range sourceResources {
+		translated, ok := translatedByKey[res.key]
+		if !ok {
+			translated = &resource{}
+		}
+
+		key := gettext.EntryKey{Id: res.value}
+		if res.isPlural && (translated.isPlural || !ok) {
+			key.Id, key.PluralId = res.pluralValues["one"], res.pluralValues["other"]
+			if len(key.Id) == 0 {
+				key.Id = key.PluralId
+			}
+		} else {
+			// a plural on only one side is kept as the raw message
+			res.isPlural, translated.isPlural = false, false
+		}
+		if key.Id != res.key {
+			key.IsContextual = true
+			key.Context = res.key
+		}
+
+		meta := res.meta
+		if meta == nil {
+			meta = translated.meta
+		}
+		entries = append(entries, createEntry(key, &res, translated, meta, &plurals))
+	}
+	return gettext.NewDocument(tag, entries)
+}
+
+func createEntry(key gettext.EntryKey, source *resource, translated *resource, meta *metadata, plurals *gettext.PluralMapping) gettext.Entry {
+	var header gettext.EntryHeader
+	if meta != nil {
+		if len(meta.Description) > 0 {
+			header.ExtractedComments = append(header.ExtractedComments, strings.Split(meta.Description, "\n")...)
+		}
+		if meta.Placeholders != nil {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, meta.Placeholders); err == nil {
+				header.ExtractedComments = append(header.ExtractedComments, placeholdersCommentPrefix+compact.String())
+			}
+		}
+	}
+
+	if !source.isPlural {
+		return gettext.NewEntry(key, header, translated.value, nil)
+	}
+
+	key.IsPlural = true
+	if len(key.PluralId) == 0 {
+		key.PluralId = key.Id
+	}
+	pluralArg := source.pluralArg
+	if translated.isPlural {
+		pluralArg = translated.pluralArg
+	}
+	if pluralArg != defaultPluralArg {
+		header.ExtractedComments = append(header.ExtractedComments, pluralArgCommentPrefix+pluralArg)
+	}
+
+	values := plurals.PluralValues(func(pluralType gettext.PluralType) string {
+		return translated.pluralValues[pluralType.Category()]
+	})
+	return gettext.NewEntry(key, header, "", values)
+}
+
+// readResources reads the messages of an arb file in order, along with their metadata
+func readResources(r io.Reader) ([]resource, error) {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil {
+		return nil, ConversionError{Reason: "Unable to read arb.", UnderlyingError: err}
+	} else if token != json.Delim('{') {
+		return nil, ConversionError{Reason: "Expected a json object."}
+	}
+
+	var result []resource
+	metadataByKey := make(map[string]*metadata)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, ConversionError{Reason: "Unable to read arb.", UnderlyingError: err}
+		}
+		key := token.(string)
+
+		switch {
+		case strings.HasPrefix(key, "@@"):
+			var ignored json.RawMessage
+			if err := decoder.Decode(&ignored); err != nil {
+				return nil, ConversionError{Key: key, Reason: "Unable to read arb.", UnderlyingError: err}
+			}
+		case strings.HasPrefix(key, "@"):
+			var meta metadata
+			if err := decoder.Decode(&meta); err != nil {
+				return nil, ConversionError{Key: key, Reason: "Unable to read metadata.", UnderlyingError: err}
+			}
+			metadataByKey[key[1:]] = &meta
+		default:
+			var value string
+			if err := decoder.Decode(&value); err != nil {
+				return nil, ConversionError{Key: key, Reason: "Only strings are supported as messages.", UnderlyingError: err}
+			}
+
+			res := resource{key: key, value: value}
+			if arg, cases, ok := parsePlural(value); ok {
+				res.isPlural = true
+				res.pluralArg = arg
+				res.pluralValues = cases
+			}
+			result = append(result, res)
+		}
+	}
+
+	// metadata usually comes after its message
+	for i := range result {
+		result[i].meta = metadataByKey[result[i].key]
+	}
+	return result, nil
+}
+
+// parsePlural parses messages that are a plural and nothing else, with only plural categories as cases
+func parsePlural(message string) (arg string, cases map[string]string, ok bool) {
+	s := strings.TrimSpace(message)
+	if !strings.HasPrefix(s, "{") {
+		return "", nil, false
+	}
+	end, ok := matchingBrace(s, 0)
+	if !ok || end != len(s)-1 {
+		return "", nil, false
+	}
+
+	parts := strings.SplitN(s[1:end], ",", 3)
+	if len(parts) != 3 || strings.TrimSpace(parts[1]) != "plural" {
+		return "", nil, false
+	}
+	arg = strings.TrimSpace(parts[0])
+
+	cases = make(map[string]string)
+	rest := parts[2]
+	for {
+		rest = strings.TrimSpace(rest)
+		if len(rest) == 0 {
+			break
+		}
+
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			return "", nil, false
+		}
+		category := strings.TrimSpace(rest[:start])
+		if _, isCategory := gettext.PluralTypeFromCategory(category); !isCategory {
+			// explicit cases like =0, or an offset
+			return "", nil, false
+		}
+		if _, found := cases[category]; found {
+			return "", nil, false
+		}
+
+		caseEnd, ok := matchingBrace(rest, start)
+		if !ok {
+			return "", nil, false
+		}
+		cases[category] = rest[start+1 : caseEnd]
+		rest = rest[caseEnd+1:]
+	}
+
+	if _, found := cases["other"]; !found {
+		return "", nil, false
+	}
+	return arg, cases, true
+}
+
+// matchingBrace finds the brace that closes the one at start, skipping over braces quoted with apostrophes, as ICU allows
+func matchingBrace(s string, start int) (int, bool) {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+			} else if i+1 < len(s) && strings.IndexByte("{}#|", s[i+1]) >= 0 {
+				end := strings.IndexByte(s[i+1:], '\'')
+				if end < 0 {
+					return 0, false
+				}
+				i += end + 1
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,199 @@
+package arb_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/arb"
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+const documentText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Shown in the app bar
+msgctxt "appName"
+msgid "Files"
+msgstr "<b>Файлы</b>"
+
+#. arb-placeholders: {"name":{"type":"String"}}
+msgctxt "hello"
+msgid "Hello {name}"
+msgstr "Привет, {name}"
+
+#. arb-plural-arg: n
+msgctxt "files"
+msgid "{n} file"
+msgid_plural "{n} files"
+msgstr[0] "{n} файл"
+msgstr[1] "{n} файла"
+msgstr[2] "{n} файлов"
+
+#, fuzzy
+msgctxt "placeholderHint"
+msgid "Write '{'name'}' for the name"
+msgstr "Пишите '{'name'}' вместо имени"
+
+msgctxt "folderItems"
+msgid "{count, plural, =0{Empty folder} other{{count} items}}"
+msgstr ""`
+
+const expectedArb = `{
+  "@@locale": "ru",
+  "appName": "<b>Файлы</b>",
+  "@appName": {
+    "description": "Shown in the app bar"
+  },
+  "hello": "Привет, {name}",
+  "@hello": {
+    "placeholders": {
+      "name": {
+        "type": "String"
+      }
+    }
+  },
+  "files": "{n, plural, one{{n} файл} few{{n} файла} many{{n} файлов} other{{n} файлов}}",
+  "@files": {
+    "placeholders": {
+      "n": {
+        "type": "int"
+      }
+    }
+  }
+}
+`
+
+func TestWrite(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := arb.Write(&buf, &doc, arb.Options{}); err != nil {
+		t.Fatal("Error writing arb: ", err)
+	}
+	if buf.String() != expectedArb {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedArb, buf.String())
+	}
+
+	buf.Reset()
+	if err := arb.Write(&buf, &doc, arb.Options{IncludeFuzzy: true}); err != nil {
+		t.Fatal("Error writing arb: ", err)
+	}
+	if s := `"placeholderHint": "Пишите '{'name'}' вместо имени"`; !strings.Contains(buf.String(), s) {
+		t.Errorf("Expected %v in:\n%v", s, buf.String())
+	}
+}
+
+func TestWrite_ReturnsError_WhenKeyInvalid(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"
+
+msgid "Not a key"
+msgstr "Не ключ"`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	err = arb.Write(&bytes.Buffer{}, &doc, arb.Options{})
+	if _, ok := err.(arb.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", arb.ConversionError{}, err, err)
+	}
+}
+
+const sourceArb = `{
+  "@@locale": "en",
+  "appName": "Files",
+  "@appName": {"description": "Shown in the app bar"},
+  "files": "{count, plural, one{{count} file} other{{count} files}}",
+  "@files": {"placeholders": {"count": {"type": "int"}}},
+  "items": "{count, plural, =0{No items} one{One item} other{{count} items}}",
+  "quoted": "{count, plural, one{'{'one'}'} other{many}}"
+}`
+
+const translationArb = `{
+  "@@locale": "ru",
+  "appName": "Файлы",
+  "files": "{count, plural, one{{count} файл} few{{count} файла} many{{count} файлов} other{{count} файла}}",
+  "items": "{count, plural, =0{Нет} other{{count} шт.}}"
+}`
+
+func TestRead(t *testing.T) {
+	doc, err := arb.Read(strings.NewReader(sourceArb), strings.NewReader(translationArb), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading arb: ", err)
+	}
+
+	// "{count} файла" for other is dropped, since it shares msgstr[2] with many
+	expected := `
+#. Shown in the app bar
+msgctxt "appName"
+msgid "Files"
+msgstr "Файлы"
+
+#. arb-placeholders: {"count":{"type":"int"}}
+msgctxt "files"
+msgid "{count} file"
+msgid_plural "{count} files"
+msgstr[0] "{count} файл"
+msgstr[1] "{count} файла"
+msgstr[2] "{count} файлов"
+
+msgctxt "items"
+msgid "{count, plural, =0{No items} one{One item} other{{count} items}}"
+msgstr "{count, plural, =0{Нет} other{{count} шт.}}"
+
+msgctxt "quoted"
+msgid "'{'one'}'"
+msgid_plural "many"
+msgstr[0] ""
+msgstr[1] ""
+msgstr[2] ""
+`
+	var buf bytes.Buffer
+	for _, entry := range doc.Entries[1:] {
+		for _, line := range entry.Lines {
+			buf.WriteString(line.RawLine)
+			buf.WriteString("\n")
+		}
+	}
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buf.String())
+	}
+}
+
+func TestRead_RoundTrips(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := arb.Write(&buf, &doc, arb.Options{}); err != nil {
+		t.Fatal("Error writing arb: ", err)
+	}
+	read, err := arb.Read(nil, bytes.NewReader(buf.Bytes()), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading arb: ", err)
+	}
+
+	var rewritten bytes.Buffer
+	if err := arb.Write(&rewritten, &read, arb.Options{}); err != nil {
+		t.Fatal("Error writing arb: ", err)
+	}
+	if rewritten.String() != expectedArb {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedArb, rewritten.String())
+	}
+}
+
+func TestRead_ReturnsError_WhenMessageNotString(t *testing.T) {
+	_, err := arb.Read(nil, strings.NewReader(`{"key": 1}`), language.Russian)
+	if _, ok := err.(arb.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", arb.ConversionError{}, err, err)
+	}
+}
@@ -0,0 +1,333 @@
+package fluent
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+// the variable of a plural select, which po files have no place for, is kept in an extracted comment with this prefix,
+// so that converting a document back gives the same select
+const pluralVariableCommentPrefix = "fluent-plural-variable: "
+
+const defaultPluralVariable = "count"
+
+// message and term identifiers, optionally followed by an attribute, like "login-input.placeholder"
+var identifierValidator = regexp.MustCompile(`^-?[a-zA-Z][a-zA-Z0-9_-]*(\.[a-zA-Z][a-zA-Z0-9_-]*)?$`)
+
+type Options struct {
+	// IncludeFuzzy writes fuzzy entries instead of skipping them.
+	IncludeFuzzy bool
+}
+
+type ConversionError struct {
+	Id     string
+	Reason string
+}
+
+func (e ConversionError) Error() string {
+	return fmt.Sprintf("Failed to convert '%v': %v", e.Id, e.Reason)
+}
+
+// Write writes the translations of the document as a fluent resource, where the msgctxt is the identifier, or the msgid for entries without one.
+// Identifiers like "login-input.placeholder" are attributes of their message, and identifiers starting with "-" are terms.
+// Plural entries become a select expression with a variant per plural category of the document, with other as the default variant.
+// Values are written as they are, so should be fluent patterns, other than lines starting with [, *, or ., which are escaped.
+// Extracted comments become message comments. Untranslated and obsolete entries are skipped, and so are plural entries until every msgstr[n] is translated,
+// since fluent falls back to another locale for missing messages, but not for missing variants.
+func Write(w io.Writer, d *gettext.Document, options Options) error {
+	plurals := sync.OnceValues(d.Header.PluralMapping)
+
+	type outMessage struct {
+		id         string
+		comments   []string
+		value      *string
+		attributes []attribute
+	}
+	var messages []*outMessage
+	messagesById := make(map[string]*outMessage)
+
+	for _, entry := range d.Entries {
+		if len(entry.Id) == 0 || entry.IsObsolete || (entry.Header.IsFuzzy() && !options.IncludeFuzzy) {
+			continue
+		}
+
+		id := entry.Id
+		if entry.IsContextual {
+			id = entry.Context
+		}
+		if !identifierValidator.MatchString(id) {
+			return ConversionError{id, "Not a valid identifier. Entries should have the identifier as the msgctxt."}
+		}
+
+		variable := defaultPluralVariable
+		var comments []string
+		for _, comment := range entry.Header.ExtractedComments {
+			if strings.HasPrefix(comment, pluralVariableCommentPrefix) {
+				variable = strings.TrimPrefix(comment, pluralVariableCommentPrefix)
+			} else {
+				comments = append(comments, comment)
+			}
+		}
+
+		value := escapePattern(entry.Value)
+		if entry.IsPlural {
+			plurals, err := plurals()
+			if err != nil {
+				return err
+			}
+			if err := plurals.ValidatePluralCount(&entry); err != nil {
+				return err
+			}
+			if !entry.IsTranslated() {
+				continue
+			}
+			value = selectPattern(variable, &plurals, entry.PluralValues)
+		}
+		if len(value) == 0 {
+			continue
+		}
+
+		messageId, attributeName, isAttribute := strings.Cut(id, ".")
+		m, ok := messagesById[messageId]
+		if !ok {
+			m = &outMessage{id: messageId}
+			messages = append(messages, m)
+			messagesById[messageId] = m
+		}
+		m.comments = append(m.comments, comments...)
+
+		if isAttribute {
+			m.attributes = append(m.attributes, attribute{attributeName, value})
+		} else {
+			m.value = &value
+		}
+	}
+
+	var sb strings.Builder
+	for i, m := range messages {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, comment := range m.comments {
+			for _, line := range strings.Split(comment, "\n") {
+				sb.WriteString(strings.TrimRight("# "+line, " "))
+				sb.WriteString("\n")
+			}
+		}
+
+		if m.value != nil {
+			writePattern(&sb, m.id, *m.value, "")
+		} else {
+			fmt.Fprintf(&sb, "%v =\n", m.id)
+		}
+		for _, a := range m.attributes {
+			writePattern(&sb, "    ."+a.name, a.value, "    ")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writePattern writes single line patterns on the same line, and others indented on the lines after
+func writePattern(sb *strings.Builder, prefix string, pattern string, indent string) {
+	if !strings.Contains(pattern, "\n") {
+		fmt.Fprintf(sb, "%v = %v\n", prefix, pattern)
+		return
+	}
+
+	fmt.Fprintf(sb, "%v =\n", prefix)
+	for _, line := range strings.Split(pattern, "\n") {
+		if len(line) > 0 {
+			sb.WriteString(indent + "    " + line)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// escapePattern escapes the start of lines that fluent would otherwise read as variants or attributes
+func escapePattern(value string) string {
+	lines := strings.Split(value, "\n")
+	for i, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " ")
+		if len(trimmed) > 0 && strings.ContainsRune("[*.", rune(trimmed[0])) {
+			lines[i+1] = fmt.Sprintf(`%v{"%c"}%v`, line[:len(line)-len(trimmed)], trimmed[0], trimmed[1:])
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func selectPattern(variable string, plurals *gettext.PluralMapping, values []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "{ $%v ->\n", variable)
+	for _, pluralType := range plurals.Categories() {
+		marker := "    "
+		if pluralType == gettext.PluralTypeOther {
+			marker = "   *"
+		}
+		value := strings.ReplaceAll(escapePattern(plurals.Value(values, pluralType)), "\n", "\n        ")
+		fmt.Fprintf(&sb, "%v[%v] %v\n", marker, pluralType.Category(), value)
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// item is a message value or attribute, which are what entries are converted from
+type item struct {
+	id       string
+	comments []string
+	value    string
+
+	isPlural     bool
+	variable     string
+	pluralValues map[string]string
+	defaultValue string
+}
+
+// Read reads a fluent resource of translations, along with the resource of the source language, which may be nil for apps that use source strings as identifiers.
+// With a source resource, identifiers become the msgctxt and the source strings the msgid, and the one and other variants of plural selects become the msgid and msgid_plural.
+// Attributes become entries of their own, like "login-input.placeholder", and message comments become extracted comments.
+// Patterns that are a select on plural categories and nothing else become plural entries, where categories without a variant get the default variant.
+// Other patterns, like selects with numbers as keys, are kept as they are.
+func Read(source io.Reader, translations io.Reader, tag language.Tag) (gettext.Document, error) {
+	var translatedItems []item
+	if translations != nil {
+		var err error
+		if translatedItems, err = readItems(translations); err != nil {
+			return gettext.Document{}, err
+		}
+	}
+
+	plurals, err := gettext.DefaultPluralMapping(tag)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+
+	if source == nil {
+		entries := make([]gettext.Entry, 0, len(translatedItems))
+		for _, it := range translatedItems {
+			entries = append(entries, createEntry(gettext.EntryKey{Id: it.id}, &it, &it, &plurals))
+		}
+		return gettext.NewDocument(tag, entries)
+	}
+
+	sourceItems, err := readItems(source)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+	translatedById := make(map[string]*item, len(translatedItems))
+	for i := range translatedItems {
+		translatedById[translatedItems[i].id] = &translatedItems[i]
+	}
+
+	entries := make([]gettext.Entry, 0, len(sourceItems))
+	for _, it := range sourceItems {
+		translated, ok := translatedById[it.id]
+		if !ok {
+			translated = &item{}
+		}
+
+		key := gettext.EntryKey{Id: it.value}
+		if it.isPlural && (translated.isPlural || !ok) {
+			key.Id, key.PluralId = it.pluralValue("one"), it.pluralValue("other")
+		} else {
+			// a select in only one of the two resources stays a plain pattern
+			it.isPlural, translated.isPlural = false, false
+		}
+		if key.Id != it.id {
+			key.IsContextual = true
+			key.Context = it.id
+		}
+
+		if it.comments == nil {
+			it.comments = translated.comments
+		}
+		entries = append(entries, createEntry(key, &it, translated, &plurals))
+	}
+	return gettext.NewDocument(tag, entries)
+}
+
+func (it *item) pluralValue(category string) string {
+	if value, ok := it.pluralValues[category]; ok {
+		return value
+	}
+	return it.defaultValue
+}
+
+func createEntry(key gettext.EntryKey, source *item, translated *item, plurals *gettext.PluralMapping) gettext.Entry {
+	header := gettext.EntryHeader{ExtractedComments: slices.Clone(source.comments)}
+	if !source.isPlural {
+		return gettext.NewEntry(key, header, translated.value, nil)
+	}
+
+	key.IsPlural = true
+	if len(key.PluralId) == 0 {
+		key.PluralId = key.Id
+	}
+	variable := source.variable
+	if translated.isPlural {
+		variable = translated.variable
+	}
+	if variable != defaultPluralVariable {
+		header.ExtractedComments = append(header.ExtractedComments, pluralVariableCommentPrefix+variable)
+	}
+
+	values := make([]string, plurals.NPlurals)
+	if translated.isPlural {
+		values = plurals.PluralValues(func(pluralType gettext.PluralType) string {
+			return translated.pluralValue(pluralType.Category())
+		})
+	}
+	return gettext.NewEntry(key, header, "", values)
+}
+
+func readItems(r io.Reader) ([]item, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := parseResource(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []item
+	for _, m := range messages {
+		if m.hasValue {
+			result = append(result, newItem(m.id, m.comments, m.value))
+		}
+		for _, a := range m.attributes {
+			result = append(result, newItem(m.id+"."+a.name, nil, a.value))
+		}
+	}
+	return result, nil
+}
+
+func newItem(id string, comments []string, value string) item {
+	it := item{id: id, comments: comments, value: value}
+
+	variable, variants, ok := parseSelect(value)
+	if !ok {
+		return it
+	}
+	pluralValues := make(map[string]string, len(variants))
+	for _, v := range variants {
+		if _, isCategory := gettext.PluralTypeFromCategory(v.key); !isCategory {
+			return it
+		}
+		pluralValues[v.key] = v.value
+		if v.isDefault {
+			it.defaultValue = v.value
+		}
+	}
+
+	it.isPlural, it.variable, it.pluralValues = true, variable, pluralValues
+	return it
+}
@@ -0,0 +1,249 @@
+package fluent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type ParseError struct {
+	Line   int
+	Reason string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("Failed to parse fluent resource at line %v: %v", e.Line, e.Reason)
+}
+
+// message is a message or term of a resource, with patterns kept as text, since they're what po values are
+type message struct {
+	id         string
+	comments   []string
+	hasValue   bool
+	value      string
+	attributes []attribute
+}
+
+type attribute struct {
+	name  string
+	value string
+}
+
+var (
+	messageFinder   = regexp.MustCompile(`^(-?[a-zA-Z][a-zA-Z0-9_-]*) *= *(.*)$`)
+	attributeFinder = regexp.MustCompile(`^\s+\.([a-zA-Z][a-zA-Z0-9_-]*) *= *(.*)$`)
+	selectFinder    = regexp.MustCompile(`^\{\s*\$([a-zA-Z][a-zA-Z0-9_-]*)\s*->[ \t]*\n`)
+	variantFinder   = regexp.MustCompile(`^\s*(\*?)\[\s*([a-zA-Z0-9_.-]+)\s*\] *(.*)$`)
+)
+
+// parseResource parses messages and terms, along with the comments right before them.
+// Group and resource comments, which are for more than one message, are skipped.
+func parseResource(s string) ([]message, error) {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	var result []message
+	var comments []string
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			comments = nil
+		case strings.HasPrefix(line, "##"):
+			comments = nil
+		case line == "#" || strings.HasPrefix(line, "# "):
+			comments = append(comments, strings.TrimPrefix(line[1:], " "))
+		default:
+			match := messageFinder.FindStringSubmatch(line)
+			if match == nil {
+				return nil, ParseError{i + 1, fmt.Sprint("Expected a message, term, or comment. Found: ", line)}
+			}
+
+			// continuation lines are indented, other than the closing brace of a placeable, which may be at the start of a line
+			end := i + 1
+			depth := braceDepth(match[2], 0)
+			for j := i + 1; j < len(lines); j++ {
+				if lines[j] != "" && !isIndented(lines[j]) && (depth == 0 || !strings.HasPrefix(lines[j], "}")) {
+					break
+				}
+				if strings.TrimSpace(lines[j]) != "" {
+					end = j + 1
+				}
+				depth = braceDepth(lines[j], depth)
+			}
+
+			m, err := parseMessage(match[1], match[2], lines[i+1:end], i+1)
+			if err != nil {
+				return nil, err
+			}
+			m.comments = comments
+			result = append(result, m)
+
+			comments = nil
+			i = end - 1
+		}
+	}
+	return result, nil
+}
+
+func parseMessage(id string, inline string, continuation []string, lineNumber int) (message, error) {
+	m := message{id: id}
+
+	// the lines of the value, followed by the lines of each attribute
+	var current *attribute
+	currentInline, currentLines := inline, []string(nil)
+	finish := func() {
+		value := normalizePattern(currentInline, currentLines)
+		if current == nil {
+			m.value, m.hasValue = value, len(value) > 0
+		} else {
+			current.value = value
+			m.attributes = append(m.attributes, *current)
+		}
+	}
+
+	depth := braceDepth(inline, 0)
+	for _, line := range continuation {
+		if depth == 0 {
+			if match := attributeFinder.FindStringSubmatch(line); match != nil {
+				finish()
+				current = &attribute{name: match[1]}
+				currentInline, currentLines = match[2], nil
+				depth = braceDepth(match[2], 0)
+				continue
+			}
+		}
+		currentLines = append(currentLines, line)
+		depth = braceDepth(line, depth)
+	}
+	finish()
+
+	if depth != 0 {
+		return message{}, ParseError{lineNumber, fmt.Sprintf("Unbalanced braces in '%v'.", id)}
+	}
+	if !m.hasValue && len(m.attributes) == 0 {
+		return message{}, ParseError{lineNumber, fmt.Sprintf("'%v' has neither a value nor attributes.", id)}
+	}
+	return m, nil
+}
+
+// normalizePattern removes the indentation of the lines after the first, like fluent does
+func normalizePattern(inline string, lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " ")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	var result []string
+	if inline = strings.TrimSpace(inline); len(inline) > 0 {
+		result = append(result, inline)
+	}
+	for _, line := range lines {
+		if len(line) >= indent && indent >= 0 {
+			line = line[indent:]
+		}
+		result = append(result, strings.TrimRight(line, " "))
+	}
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
+
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ")
+}
+
+// placeableEnd finds the brace that closes the placeable the pattern starts with
+func placeableEnd(pattern string) int {
+	end := -1
+	scanBraces(pattern, 0, func(i int, depth int) bool {
+		if depth == 0 {
+			end = i
+			return false
+		}
+		return true
+	})
+	return end
+}
+
+// braceDepth is the placeable depth after the text, starting from the depth before it
+func braceDepth(s string, depth int) int {
+	return scanBraces(s, depth, func(int, int) bool { return true })
+}
+
+// scanBraces calls onClose for each closing brace, skipping braces in string literals, like {"{"}, which only appear in placeables
+func scanBraces(s string, depth int, onClose func(i int, depth int) bool) int {
+	inString := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"' && depth > 0:
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if !onClose(i, depth) {
+				return depth
+			}
+		}
+	}
+	return depth
+}
+
+type variant struct {
+	key       string
+	isDefault bool
+	value     string
+}
+
+// parseSelect parses patterns that are a select expression on a variable and nothing else
+func parseSelect(pattern string) (variable string, variants []variant, ok bool) {
+	match := selectFinder.FindStringSubmatch(pattern)
+	if match == nil || placeableEnd(pattern) != len(pattern)-1 {
+		return "", nil, false
+	}
+	variable = match[1]
+
+	body := strings.Split(pattern[len(match[0]):len(pattern)-1], "\n")
+	var currentInline string
+	var currentLines []string
+	var current *variant
+	finish := func() {
+		if current != nil {
+			current.value = normalizePattern(currentInline, currentLines)
+			variants = append(variants, *current)
+		}
+	}
+
+	depth := 0
+	for _, line := range body {
+		if depth == 0 {
+			if match := variantFinder.FindStringSubmatch(line); match != nil {
+				finish()
+				current = &variant{key: match[2], isDefault: match[1] == "*"}
+				currentInline, currentLines = match[3], nil
+				depth = braceDepth(match[3], 0)
+				continue
+			}
+		}
+		if current == nil {
+			if strings.TrimSpace(line) != "" {
+				return "", nil, false
+			}
+			continue
+		}
+		currentLines = append(currentLines, line)
+		depth = braceDepth(line, depth)
+	}
+	finish()
+
+	if depth != 0 || len(variants) == 0 {
+		return "", nil, false
+	}
+	return variable, variants, true
+}
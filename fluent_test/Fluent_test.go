@@ -0,0 +1,228 @@
+package fluent_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/fluent"
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+const documentText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Also the window title
+msgctxt "app-name"
+msgid "Files"
+msgstr "Файлы"
+
+msgctxt "-brand"
+msgid "Example"
+msgstr "Пример"
+
+msgctxt "-brand.gender"
+msgid "neuter"
+msgstr "masculine"
+
+msgctxt "login-input.placeholder"
+msgid "email@example.com"
+msgstr "почта@example.com"
+
+msgctxt "intro"
+msgid "Welcome\n[beta]"
+msgstr "Добро пожаловать\n[бета]"
+
+#. fluent-plural-variable: n
+msgctxt "files-left"
+msgid "{ $n } file left"
+msgid_plural "{ $n } files left"
+msgstr[0] "Остался { $n } файл"
+msgstr[1] "Осталось { $n } файла"
+msgstr[2] "Осталось { $n } файлов"
+
+#, fuzzy
+msgctxt "about"
+msgid "About { -brand }"
+msgstr "О { -brand }"
+
+msgctxt "login-input.aria-label"
+msgid "Email address"
+msgstr ""`
+
+const expectedFtl = `# Also the window title
+app-name = Файлы
+
+-brand = Пример
+    .gender = masculine
+
+login-input =
+    .placeholder = почта@example.com
+
+intro =
+    Добро пожаловать
+    {"["}бета]
+
+files-left =
+    { $n ->
+        [one] Остался { $n } файл
+        [few] Осталось { $n } файла
+        [many] Осталось { $n } файлов
+       *[other] Осталось { $n } файлов
+    }
+`
+
+func TestWrite(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := fluent.Write(&buf, &doc, fluent.Options{}); err != nil {
+		t.Fatal("Error writing ftl: ", err)
+	}
+	if buf.String() != expectedFtl {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedFtl, buf.String())
+	}
+
+	buf.Reset()
+	if err := fluent.Write(&buf, &doc, fluent.Options{IncludeFuzzy: true}); err != nil {
+		t.Fatal("Error writing ftl: ", err)
+	}
+	if s := "about = О { -brand }\n"; !strings.Contains(buf.String(), s) {
+		t.Errorf("Expected %v in:\n%v", s, buf.String())
+	}
+}
+
+func TestWrite_ReturnsError_WhenIdentifierInvalid(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"
+
+msgid "Not an identifier"
+msgstr "Не идентификатор"`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	err = fluent.Write(&bytes.Buffer{}, &doc, fluent.Options{})
+	if _, ok := err.(fluent.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", fluent.ConversionError{}, err, err)
+	}
+}
+
+const sourceFtl = `### Resource comment
+
+## Group comment
+
+# Also the window title
+app-name = Files
+login-input = Log in
+    .placeholder = email@example.com
+files-left =
+    { $count ->
+        [one] { $count } file left
+       *[other] { $count } files left
+    }
+emails =
+    { $unread ->
+        [0] No new emails
+       *[other] { $unread } new emails
+    }
+`
+
+const translationFtl = `app-name = Файлы
+login-input = Войти
+    .placeholder = почта@example.com
+files-left = { $count ->
+    [one] Остался { $count } файл
+    [few] Осталось { $count } файла
+   *[other]
+        Осталось
+        { $count } файлов
+}
+`
+
+func TestRead(t *testing.T) {
+	doc, err := fluent.Read(strings.NewReader(sourceFtl), strings.NewReader(translationFtl), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading ftl: ", err)
+	}
+
+	// many has no variant, so gets the default one
+	expected := `
+#. Also the window title
+msgctxt "app-name"
+msgid "Files"
+msgstr "Файлы"
+
+msgctxt "login-input"
+msgid "Log in"
+msgstr "Войти"
+
+msgctxt "login-input.placeholder"
+msgid "email@example.com"
+msgstr "почта@example.com"
+
+msgctxt "files-left"
+msgid "{ $count } file left"
+msgid_plural "{ $count } files left"
+msgstr[0] "Остался { $count } файл"
+msgstr[1] "Осталось { $count } файла"
+msgstr[2] ""
+"Осталось\n"
+"{ $count } файлов"
+
+msgctxt "emails"
+msgid ""
+"{ $unread ->\n"
+"    [0] No new emails\n"
+"   *[other] { $unread } new emails\n"
+"}"
+msgstr ""
+`
+	var buf bytes.Buffer
+	for _, entry := range doc.Entries[1:] {
+		for _, line := range entry.Lines {
+			buf.WriteString(line.RawLine)
+			buf.WriteString("\n")
+		}
+	}
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buf.String())
+	}
+}
+
+func TestRead_RoundTrips(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := fluent.Write(&buf, &doc, fluent.Options{}); err != nil {
+		t.Fatal("Error writing ftl: ", err)
+	}
+	read, err := fluent.Read(nil, bytes.NewReader(buf.Bytes()), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading ftl: ", err)
+	}
+
+	var rewritten bytes.Buffer
+	if err := fluent.Write(&rewritten, &read, fluent.Options{}); err != nil {
+		t.Fatal("Error writing ftl: ", err)
+	}
+	if rewritten.String() != expectedFtl {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedFtl, rewritten.String())
+	}
+}
+
+func TestRead_ReturnsError_WhenMalformed(t *testing.T) {
+	_, err := fluent.Read(nil, strings.NewReader("key = { $count ->\n    *[other] a\n"), language.Russian)
+	if _, ok := err.(fluent.ParseError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", fluent.ParseError{}, err, err)
+	}
+}
@@ -0,0 +1,127 @@
+package gettext
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/text/unicode/cldr"
+)
+
+type CldrPluralDataError struct {
+	Source          string
+	Reason          string
+	UnderlyingError error
+}
+
+func (e CldrPluralDataError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to load CLDR plural data from '%v': %v Underlying error: %v", e.Source, e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to load CLDR plural data from '%v': %v", e.Source, e.Reason)
+}
+
+// pluralData is plural data loaded from a CLDR release, which is used instead of the built-in data.
+// it is keyed by the CLDR plurals type, either "cardinal" or "ordinal", then by locale.
+type pluralData map[string]map[string]PluralRulesDefinition
+
+var (
+	loadedPluralDataMutex sync.RWMutex
+	loadedPluralData      pluralData
+)
+
+// LoadCldrPluralDataFromPath loads plural, ordinal, and plural range rules from an extracted CLDR release,
+// such as the contents of core.zip, and uses them as the default rules.
+// Locales the release doesn't have rules for fall back to the built-in rules.
+func LoadCldrPluralDataFromPath(path string) error {
+	return loadCldrPluralData(path, func(d *cldr.Decoder) (*cldr.CLDR, error) {
+		return d.DecodePath(path)
+	})
+}
+
+// LoadCldrPluralDataFromZip is like LoadCldrPluralDataFromPath, but for an unextracted release, such as core.zip.
+func LoadCldrPluralDataFromZip(r io.Reader) error {
+	return loadCldrPluralData("zip", func(d *cldr.Decoder) (*cldr.CLDR, error) {
+		return d.DecodeZip(r)
+	})
+}
+
+// ResetCldrPluralData goes back to using only the built-in rules.
+func ResetCldrPluralData() {
+	loadedPluralDataMutex.Lock()
+	defer loadedPluralDataMutex.Unlock()
+
+	loadedPluralData = nil
+}
+
+func loadCldrPluralData(source string, decode func(*cldr.Decoder) (*cldr.CLDR, error)) error {
+	var d cldr.Decoder
+	d.SetDirFilter("supplemental")
+	d.SetSectionFilter("plurals")
+
+	data, err := decode(&d)
+	if err != nil {
+		return CldrPluralDataError{source, "Unable to decode CLDR data.", err}
+	}
+
+	p, err := tryLoadPluralData(data)
+	if err != nil {
+		return CldrPluralDataError{source, "Unable to read plural rules.", err}
+	}
+	if len(p["cardinal"]) == 0 && len(p["ordinal"]) == 0 {
+		return CldrPluralDataError{Source: source, Reason: "No plural rules found."}
+	}
+
+	// unlike the built-in rules, which are checked by tests, these are checked up front
+	// so that parsing them later doesn't panic
+	for _, definitions := range p {
+		for locale, definition := range definitions {
+			if err := definition.Validate(); err != nil {
+				return CldrPluralDataError{source, fmt.Sprint("Rules for locale '", locale, "' failed validation."), err}
+			}
+		}
+	}
+
+	loadedPluralDataMutex.Lock()
+	defer loadedPluralDataMutex.Unlock()
+
+	loadedPluralData = p
+	return nil
+}
+
+// the loaders panic, since they were written for the built-in data, so we recover like tryParsePluralRule does
+func tryLoadPluralData(data *cldr.CLDR) (result pluralData, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	result = pluralData{
+		"cardinal": loadCardinalRulesDefinitions(data),
+		"ordinal":  loadPluralRulesDefinitions(data, "ordinal"),
+	}
+	return
+}
+
+// lookupDefinition looks in the registered data, then the loaded data, if any, then the built-in data,
+// going from the most specific locale to the least, so that a built-in pt_PT is used over a registered pt.
+func lookupDefinition(localeCode string, pluralsType string, builtIn map[string]PluralRulesDefinition) (PluralRulesDefinition, bool) {
+	registeredPluralDataMutex.RLock()
+	registered := registeredPluralData[pluralsType]
+	registeredPluralDataMutex.RUnlock()
+
+	loadedPluralDataMutex.RLock()
+	loaded := loadedPluralData[pluralsType]
+	loadedPluralDataMutex.RUnlock()
+
+	for _, locale := range localeCandidates(localeCode) {
+		for _, definitions := range []map[string]PluralRulesDefinition{registered, loaded, builtIn} {
+			if result, ok := definitions[locale]; ok {
+				return result, true
+			}
+		}
+	}
+
+	return PluralRulesDefinition{}, false
+}
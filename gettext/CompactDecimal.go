@@ -0,0 +1,62 @@
+package gettext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// CompactDecimal is a number displayed in compact exponential form, such as "1.2 million" being 1.2c6.
+// The number itself is Significand×10^Exponent, but plural rules can also see the exponent through the c and e operands.
+type CompactDecimal struct {
+	Significand decimal.Decimal
+	Exponent    int32
+}
+
+type CompactDecimalParseError struct {
+	Value  string
+	Reason string
+}
+
+// ParseCompactDecimal parses numbers in the form CLDR samples use, such as 1.2c6, 1.2e6, or just 1.2.
+func ParseCompactDecimal(s string) (CompactDecimal, error) {
+	rawSignificand, rawExponent, hasExponent := s, "", false
+	if i := strings.IndexAny(s, "ce"); i >= 0 {
+		rawSignificand, rawExponent, hasExponent = s[:i], s[i+1:], true
+	}
+
+	significand, err := decimal.NewFromString(rawSignificand)
+	if err != nil {
+		return CompactDecimal{}, CompactDecimalParseError{s, err.Error()}
+	}
+
+	if !hasExponent {
+		return CompactDecimal{Significand: significand}, nil
+	}
+
+	exponent, err := strconv.ParseInt(rawExponent, 10, 32)
+	if err != nil {
+		return CompactDecimal{}, CompactDecimalParseError{s, err.Error()}
+	}
+
+	return CompactDecimal{significand, int32(exponent)}, nil
+}
+
+func (c CompactDecimal) Decimal() decimal.Decimal {
+	return c.Significand.Shift(c.Exponent)
+}
+
+func (c CompactDecimal) String() string {
+	// unlike String, StringFixed keeps trailing zeroes, which plural rules care about
+	s := c.Significand.StringFixed(max(0, -c.Significand.Exponent()))
+	if c.Exponent == 0 {
+		return s
+	}
+	return fmt.Sprint(s, "c", c.Exponent)
+}
+
+func (e CompactDecimalParseError) Error() string {
+	return fmt.Sprintf("Failed to parse compact decimal '%v': %v", e.Value, e.Reason)
+}
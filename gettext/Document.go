@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"strings"
+
+	"golang.org/x/text/language"
 )
 
 type Document struct {
@@ -12,7 +14,26 @@ type Document struct {
 	Entries []Entry
 }
 
+type DocumentOptions struct {
+	// ValidatePluralRules checks the header's X-PluralRules-* and X-OrdinalRules-* rules against their @integer and @decimal samples,
+	// as well as checking that the rules don't overlap and that the other rule catches everything else.
+	ValidatePluralRules bool
+
+	// ValidatePluralCounts checks that each plural entry has as many msgstr[n] as the document expects; see ValidatePluralCounts.
+	ValidatePluralCounts bool
+}
+
+// NewDocument creates a document for the language, with a header created by NewHeaderEntry followed by the entries,
+// which are usually created with NewEntry.
+func NewDocument(tag language.Tag, entries []Entry) (Document, error) {
+	return CreateDocument(append([]Entry{NewHeaderEntry(tag)}, entries...))
+}
+
 func CreateDocument(entries []Entry) (Document, error) {
+	return CreateDocumentWithOptions(entries, DocumentOptions{})
+}
+
+func CreateDocumentWithOptions(entries []Entry, options DocumentOptions) (Document, error) {
 	if len(entries) == 0 {
 		return Document{}, DocumentMissingHeaderError{}
 	}
@@ -20,11 +41,58 @@ func CreateDocument(entries []Entry) (Document, error) {
 		return Document{}, DocumentMissingHeaderError{}
 	}
 
-	header, err := CreateHeaderFromEntry(entries[0])
+	header, err := CreateHeaderFromEntryWithOptions(entries[0], options)
 	if err != nil {
 		return Document{}, err
 	}
-	return Document{header, entries}, nil
+
+	document := Document{header, entries}
+	if options.ValidatePluralCounts {
+		if err := document.ValidatePluralCounts(); err != nil {
+			return Document{}, err
+		}
+	}
+	return document, nil
+}
+
+// ValidatePluralCounts checks that each plural entry has the msgstr[n] that the header's PluralMapping expects,
+// which is the nplurals of the Plural-Forms, if present, or otherwise the number of plural forms that integers reach.
+// Obsolete entries are skipped, since they aren't used.
+func (d *Document) ValidatePluralCounts() error {
+	plurals, err := d.Header.PluralMapping()
+	if err != nil {
+		return err
+	}
+
+	var errors []EntryPluralCountError
+	for _, entry := range d.Entries {
+		if entry.IsObsolete {
+			continue
+		}
+		if err := plurals.ValidatePluralCount(&entry); err != nil {
+			errors = append(errors, err.(EntryPluralCountError))
+		}
+	}
+
+	if len(errors) > 0 {
+		return DocumentPluralCountError{errors}
+	}
+	return nil
+}
+
+// WriteTo writes the lines of each entry, so parsed documents are written back the way they were read.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for _, entry := range d.Entries {
+		for _, line := range entry.Lines {
+			n, err := io.WriteString(w, line.RawLine+"\n")
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
 }
 
 func ParseDocumentString(d string) (Document, error) {
@@ -32,6 +100,10 @@ func ParseDocumentString(d string) (Document, error) {
 }
 
 func ParseDocument(r io.Reader) (Document, error) {
+	return ParseDocumentWithOptions(r, DocumentOptions{})
+}
+
+func ParseDocumentWithOptions(r io.Reader, options DocumentOptions) (Document, error) {
 	scanner := bufio.NewScanner(r)
 
 	lineCounter := 1
@@ -114,7 +186,7 @@ func ParseDocument(r io.Reader) (Document, error) {
 		return Document{}, err
 	}
 
-	return CreateDocument(ctx.Entries)
+	return CreateDocumentWithOptions(ctx.Entries, options)
 }
 
 type DocumentMissingHeaderError struct{}
@@ -123,6 +195,18 @@ func (e DocumentMissingHeaderError) Error() string {
 	return "The first entry of a document must be a header, having an empty id."
 }
 
+type DocumentPluralCountError struct {
+	Errors []EntryPluralCountError
+}
+
+func (e DocumentPluralCountError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprint("Entries have the wrong number of plural values: ", strings.Join(messages, " "))
+}
+
 type DocumentParseError struct {
 	Reason          string
 	UnderlyingError error
@@ -9,23 +9,33 @@ import (
 )
 
 type DocumentHeader struct {
-	Tag         language.Tag
-	PluralRules PluralRules
+	Tag          language.Tag
+	PluralRules  PluralRules
+	OrdinalRules PluralRules
+
+	// PluralForms is the header's Plural-Forms, if present. It's only parsed by ComparePluralForms,
+	// since files often have malformed ones, like xgettext's "nplurals=INTEGER; plural=EXPRESSION;" placeholder.
+	PluralForms string
 }
 
 type DocumentHeaderParseError struct {
-	Entry  Entry
-	Reason string
+	Entry           Entry
+	Reason          string
+	UnderlyingError error
 }
 
 func CreateHeaderFromEntry(entry Entry) (DocumentHeader, error) {
+	return CreateHeaderFromEntryWithOptions(entry, DocumentOptions{})
+}
+
+func CreateHeaderFromEntryWithOptions(entry Entry, options DocumentOptions) (DocumentHeader, error) {
 	if entry.IsContextual {
-		return DocumentHeader{}, DocumentHeaderParseError{entry, "Header must not be contextual."}
+		return DocumentHeader{}, DocumentHeaderParseError{Entry: entry, Reason: "Header must not be contextual."}
 	}
 
 	matches := languageExtractor.FindStringSubmatch(entry.Value)
 	if matches == nil {
-		return DocumentHeader{}, DocumentHeaderParseError{entry, "Language not found."}
+		return DocumentHeader{}, DocumentHeaderParseError{Entry: entry, Reason: "Language not found."}
 	}
 	rawLanguageValue := matches[1]
 	var languageValue string
@@ -40,7 +50,7 @@ func CreateHeaderFromEntry(entry Entry) (DocumentHeader, error) {
 			if variant, ok := getTextVariantMap[getTextVariant]; ok {
 				languageValue = fmt.Sprint(languageValue, "-", variant)
 			} else {
-				return DocumentHeader{}, DocumentHeaderParseError{entry, fmt.Sprint("Unable to parse variant of language: ", rawLanguageValue)}
+				return DocumentHeader{}, DocumentHeaderParseError{Entry: entry, Reason: fmt.Sprint("Unable to parse variant of language: ", rawLanguageValue)}
 			}
 		}
 
@@ -49,9 +59,124 @@ func CreateHeaderFromEntry(entry Entry) (DocumentHeader, error) {
 		}
 	}
 
+	pluralRulesDefinition := extractPluralRulesDefinition(entry.Value, pluralRuleExtractor)
+	pluralRules, err := pluralRulesDefinition.compile()
+	if err != nil {
+		return DocumentHeader{}, DocumentHeaderParseError{entry, "Unable to parse plural rules.", err}
+	}
+
+	ordinalRulesDefinition := extractPluralRulesDefinition(entry.Value, ordinalRuleExtractor)
+	ordinalRules, err := ordinalRulesDefinition.compile()
+	if err != nil {
+		return DocumentHeader{}, DocumentHeaderParseError{entry, "Unable to parse ordinal rules.", err}
+	}
+
+	if options.ValidatePluralRules {
+		if err := pluralRulesDefinition.Validate(); err != nil {
+			return DocumentHeader{}, DocumentHeaderParseError{entry, "Plural rules failed validation.", err}
+		}
+		if err := ordinalRulesDefinition.Validate(); err != nil {
+			return DocumentHeader{}, DocumentHeaderParseError{entry, "Ordinal rules failed validation.", err}
+		}
+	}
+
+	var pluralForms string
+	if matches := pluralFormsExtractor.FindStringSubmatch(entry.Value); matches != nil {
+		pluralForms = matches[1]
+	}
+
+	return DocumentHeader{
+		Tag:          language.Make(languageValue),
+		PluralRules:  pluralRules,
+		OrdinalRules: ordinalRules,
+		PluralForms:  pluralForms,
+	}, nil
+}
+
+// ComparePluralForms checks the header's Plural-Forms against its plural rules; see PluralForms.Compare.
+// Like Document.ValidatePluralCounts, the default rules of the header's language are used when the header has no X-PluralRules-* rules.
+// Headers without Plural-Forms have nothing to compare, so are always fine, and malformed ones return a PluralFormsParseError.
+func (h *DocumentHeader) ComparePluralForms() error {
+	if len(h.PluralForms) == 0 {
+		return nil
+	}
+
+	forms, err := ParsePluralForms(h.PluralForms)
+	if err != nil {
+		return err
+	}
+	rules, err := h.PluralRulesOrDefault()
+	if err != nil {
+		return err
+	}
+	return forms.Compare(&rules)
+}
+
+// PluralRulesOrDefault returns the header's X-PluralRules-* rules, falling back to the default rules of the header's language.
+func (h *DocumentHeader) PluralRulesOrDefault() (PluralRules, error) {
+	if h.PluralRules.rules != [PluralTypeOther + 1]*PluralRule{} {
+		return h.PluralRules, nil
+	}
+
+	definition, err := GetDefaultPluralRulesDefinition(h.Tag.String())
+	if err != nil {
+		return PluralRules{}, err
+	}
+	return definition.Parse(), nil
+}
+
+// NewHeaderEntry creates the header entry for a new document, with the language in the form gettext uses, such as "sr_RS@latin".
+// For languages with default plural rules, the header also has the rules, as X-PluralRules-* fields,
+// and the Plural-Forms that gettext tools need, whose msgstr[n] are those of DefaultPluralMapping.
+func NewHeaderEntry(tag language.Tag) Entry {
+	var sb strings.Builder
+	fmt.Fprint(&sb,
+		"Language: ", gettextLanguage(tag), "\n",
+		"MIME-Version: 1.0\n",
+		"Content-Type: text/plain; charset=UTF-8\n",
+		"Content-Transfer-Encoding: 8bit\n")
+
+	if definition, err := GetDefaultPluralRulesDefinition(tag.String()); err == nil {
+		rules := definition.Parse()
+		forms := rules.PluralForms()
+		fmt.Fprint(&sb, "Plural-Forms: ", forms.String(), "\n", rules.HeaderFields(PluralRulesHeaderPrefix))
+	}
+
+	return NewHeaderEntryFromValue(sb.String())
+}
+
+// NewHeaderEntryFromValue creates the header entry for a new document from the header's fields,
+// such as "Language: ja\nContent-Type: text/plain; charset=UTF-8\n".
+func NewHeaderEntryFromValue(value string) Entry {
+	entry := NewEntry(EntryKey{}, EntryHeader{}, value, nil)
+	// the header is the first entry, so has no blank line separating it from a previous entry
+	entry.Lines = entry.Lines[1:]
+	return entry
+}
+
+// gettextLanguage is the reverse of how CreateHeaderFromEntry reads the language
+func gettextLanguage(tag language.Tag) string {
+	base, script, region := tag.Raw()
+
+	result := base.String()
+	if region != (language.Region{}) {
+		result = fmt.Sprint(result, "_", region)
+	}
+	if script != (language.Script{}) {
+		for variant, variantScript := range getTextVariantMap {
+			if variantScript == script.String() {
+				result = fmt.Sprint(result, "@", variant)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func extractPluralRulesDefinition(headerValue string, extractor *regexp.Regexp) PluralRulesDefinition {
 	var d PluralRulesDefinition
 
-	for _, rawPluralRules := range pluralRuleExtractor.FindAllStringSubmatch(entry.Value, -1) {
+	for _, rawPluralRules := range extractor.FindAllStringSubmatch(headerValue, -1) {
 		rule := rawPluralRules[2]
 		switch strings.ToLower(rawPluralRules[1]) {
 		case "zero":
@@ -69,21 +194,23 @@ func CreateHeaderFromEntry(entry Entry) (DocumentHeader, error) {
 		}
 	}
 
-	return DocumentHeader{
-		language.Make(languageValue),
-		d.Parse(),
-	}, nil
+	return d
 }
 
 func (e DocumentHeaderParseError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to parse document header: %v Underlying error: %v", e.Reason, e.UnderlyingError)
+	}
 	return fmt.Sprint("Failed to parse document header: ", e.Reason)
 }
 
 var (
-	languageExtractor   = regexp.MustCompile(`(?im)^Language: (.+)$`)
-	languageParser      = regexp.MustCompile(`(?i)([a-z]+)(?:_([a-z]+))?(?:@([a-z]+))?`)
-	pluralRuleExtractor = regexp.MustCompile(`(?im)^X-PluralRules-([a-z]+): *(.*)$`)
-	getTextVariantMap   = map[string]string{
+	languageExtractor    = regexp.MustCompile(`(?im)^Language: (.+)$`)
+	languageParser       = regexp.MustCompile(`(?i)([a-z]+)(?:_([a-z]+))?(?:@([a-z]+))?`)
+	pluralRuleExtractor  = regexp.MustCompile(`(?im)^X-PluralRules-([a-z]+): *(.*)$`)
+	ordinalRuleExtractor = regexp.MustCompile(`(?im)^X-OrdinalRules-([a-z]+): *(.*)$`)
+	pluralFormsExtractor = regexp.MustCompile(`(?im)^Plural-Forms: *(.*)$`)
+	getTextVariantMap    = map[string]string{
 		"latin":       "Latn",
 		"cyrillic":    "Cyrl",
 		"adlam":       "Adlm",
@@ -2,6 +2,7 @@ package gettext
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -36,6 +37,12 @@ type EntryParseError struct {
 	Reason string
 }
 
+type EntryPluralCountError struct {
+	EntryKey EntryKey
+	Expected int
+	Actual   int
+}
+
 // the original c# implementation has a keyword->value map,
 // which makes this method a bit more maintainable when supporting new keywords
 // but in practice, this doesn't happen, and hard-coded keywords provide a simpler implementation
@@ -47,6 +54,8 @@ func ParseEntry(lines []Line) (Entry, error) {
 
 	//these will be plopped into the entry later
 	var context, id, value, pluralId strings.Builder
+	// an empty msgctxt is still a msgctxt, so whether the keyword was found is tracked separately
+	var isContextual bool
 	// in practice, there are 6 plural types and special zero, so usually 7 or less
 	pluralValues := make(map[int]*strings.Builder, 7)
 
@@ -85,6 +94,7 @@ func ParseEntry(lines []Line) (Entry, error) {
 				switch line.Keyword.Keyword {
 				case "msgctxt":
 					currentValue = &context
+					isContextual = true
 				case "msgid":
 					currentValue = &id
 				case "msgid_plural":
@@ -134,7 +144,7 @@ func ParseEntry(lines []Line) (Entry, error) {
 		return Entry{}, EntryParseError{fmt.Sprint("Found unsupported keywords in entry: ", s)}
 	}
 
-	entry.IsContextual = context.Cap() > 0
+	entry.IsContextual = isContextual
 	entry.Context = context.String()
 	entry.Id = id.String()
 	entry.Value = value.String()
@@ -149,6 +159,22 @@ func ParseEntry(lines []Line) (Entry, error) {
 	return entry, nil
 }
 
+// IsTranslated is whether the entry has a msgstr, or, for plural entries, whether every msgstr[n] has one.
+func (e *Entry) IsTranslated() bool {
+	if !e.IsPlural {
+		return len(e.Value) > 0
+	}
+	return len(e.PluralValues) > 0 && !slices.Contains(e.PluralValues, "")
+}
+
+// PluralSource is the source string of a plural category, which is the msgid for the one category and the msgid_plural otherwise.
+func (k *EntryKey) PluralSource(pluralType PluralType) string {
+	if pluralType == PluralTypeOne {
+		return k.Id
+	}
+	return k.PluralId
+}
+
 func (e EntryLineParseError) Error() string {
 	return fmt.Sprintf("Failed to parse line for entry. %v Line: %v", e.Reason, e.Line.RawLine)
 }
@@ -156,3 +182,52 @@ func (e EntryLineParseError) Error() string {
 func (e EntryParseError) Error() string {
 	return fmt.Sprintf("Failed to parse entry. %v", e.Reason)
 }
+
+func (e EntryPluralCountError) Error() string {
+	return fmt.Sprintf("Expected '%v' plural values based on the plural forms, but found '%v' for entry: %+v", e.Expected, e.Actual, e.EntryKey)
+}
+
+// NewEntry creates an entry, along with the lines it would be written as, such as for writing a Document created in code.
+// The lines start with a blank line, separating the entry from the one before it.
+func NewEntry(key EntryKey, header EntryHeader, value string, pluralValues []string) Entry {
+	lines := []Line{CompleteLine(Keyword{IsEmpty: true}, LineValue{IsEmpty: true}, Comment{IsEmpty: true})}
+	lines = append(lines, header.lines()...)
+
+	if key.IsContextual {
+		lines = append(lines, valueLines(SimpleKeyword("msgctxt"), key.Context)...)
+	}
+	lines = append(lines, valueLines(SimpleKeyword("msgid"), key.Id)...)
+
+	if key.IsPlural {
+		lines = append(lines, valueLines(SimpleKeyword("msgid_plural"), key.PluralId)...)
+		for i, pluralValue := range pluralValues {
+			lines = append(lines, valueLines(IndexedKeyword("msgstr", i), pluralValue)...)
+		}
+	} else {
+		lines = append(lines, valueLines(SimpleKeyword("msgstr"), value)...)
+		pluralValues = nil
+	}
+
+	return Entry{
+		EntryKey:     key,
+		Header:       header,
+		Value:        value,
+		PluralValues: pluralValues,
+		Lines:        lines,
+	}
+}
+
+// valueLines puts multi-line values on their own lines after an empty keyworded line, like gettext tools do
+func valueLines(keyword Keyword, value string) []Line {
+	if i := strings.Index(value, "\n"); i < 0 || i == len(value)-1 {
+		return []Line{KeywordedValueLine(keyword, LineValueFromValue(value))}
+	}
+
+	lines := []Line{KeywordedValueLine(keyword, LineValueFromValue(""))}
+	for _, v := range strings.SplitAfter(value, "\n") {
+		if len(v) > 0 {
+			lines = append(lines, ValueLine(LineValueFromValue(v)))
+		}
+	}
+	return lines
+}
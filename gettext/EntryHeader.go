@@ -6,6 +6,11 @@ import (
 )
 
 type EntryHeader struct {
+	// TranslatorComments are from "# " comments, which are written by translators
+	TranslatorComments []string
+	// ExtractedComments are from "#. " comments, which are written by extraction tools, usually from comments in the source code
+	ExtractedComments []string
+
 	References []string
 	Flags      []string
 }
@@ -22,10 +27,15 @@ func ExtractEntryHeader(lines []Line) EntryHeader {
 		}
 	}
 
-	references := make([]string, len(headerComments))
+	var translatorComments, extractedComments, references []string
 	for _, comment := range headerComments {
-		if strings.HasPrefix(comment, ":") {
+		switch {
+		case strings.HasPrefix(comment, ":"):
 			references = append(references, strings.TrimSpace(comment[1:]))
+		case strings.HasPrefix(comment, "."):
+			extractedComments = append(extractedComments, strings.TrimPrefix(comment[1:], " "))
+		case len(comment) == 0 || comment[0] == ' ':
+			translatorComments = append(translatorComments, strings.TrimPrefix(comment, " "))
 		}
 	}
 
@@ -44,5 +54,51 @@ func ExtractEntryHeader(lines []Line) EntryHeader {
 		}
 	}
 
-	return EntryHeader{References: references, Flags: flags}
+	return EntryHeader{
+		TranslatorComments: translatorComments,
+		ExtractedComments:  extractedComments,
+		References:         references,
+		Flags:              flags,
+	}
+}
+
+// IsFuzzy is true for entries marked with the fuzzy flag, whose translations need review and, like with msgfmt, shouldn't be used yet.
+func (h *EntryHeader) IsFuzzy() bool {
+	return slices.Contains(h.Flags, "fuzzy")
+}
+
+// SetFuzzy adds or removes the fuzzy flag, which, like gettext tools do, is added before other flags.
+// The flags are copied, so headers copied from an entry don't change the entry.
+func (h *EntryHeader) SetFuzzy(fuzzy bool) {
+	flags := slices.DeleteFunc(slices.Clone(h.Flags), func(flag string) bool { return flag == "fuzzy" })
+	if fuzzy {
+		flags = append([]string{"fuzzy"}, flags...)
+	}
+	h.Flags = flags
+}
+
+// lines creates the comment lines of the header, in the order gettext tools write them
+func (h *EntryHeader) lines() []Line {
+	var lines []Line
+	addComments := func(prefix string, comments []string) {
+		for _, comment := range comments {
+			for _, c := range strings.Split(comment, "\n") {
+				if len(c) == 0 {
+					// no trailing space for empty comments, like "#"
+					lines = append(lines, CommentLine(Comment{Comment: strings.TrimSpace(prefix)}))
+					continue
+				}
+				lines = append(lines, CommentLine(Comment{Comment: prefix + c}))
+			}
+		}
+	}
+
+	addComments(" ", h.TranslatorComments)
+	addComments(". ", h.ExtractedComments)
+	addComments(": ", h.References)
+	if len(h.Flags) > 0 {
+		addComments(", ", []string{strings.Join(h.Flags, ", ")})
+	}
+
+	return lines
 }
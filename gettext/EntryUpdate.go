@@ -0,0 +1,114 @@
+package gettext
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EntryUpdate is a change to the translation or header of an entry, where nil fields are left as they are.
+type EntryUpdate struct {
+	Value        *string
+	PluralValues []string
+	Header       *EntryHeader
+}
+
+type EntryUpdateError struct {
+	EntryKey EntryKey
+	Reason   string
+}
+
+func (e EntryUpdateError) Error() string {
+	return fmt.Sprintf("Failed to update entry: %v Entry: %+v", e.Reason, e.EntryKey)
+}
+
+// FindEntry finds the entry with the msgctxt and msgid of the key, which are what identify entries to gettext tools.
+// The header and obsolete entries are skipped.
+func (d *Document) FindEntry(key EntryKey) (*Entry, bool) {
+	if i, ok := d.findEntryIndex(key); ok {
+		return &d.Entries[i], true
+	}
+	return nil, false
+}
+
+func (d *Document) findEntryIndex(key EntryKey) (int, bool) {
+	for i := 1; i < len(d.Entries); i++ {
+		entry := &d.Entries[i]
+		if !entry.IsObsolete && entry.IsContextual == key.IsContextual && entry.Context == key.Context && entry.Id == key.Id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// UpdateEntry applies the update to the entry with the msgctxt and msgid of the key, returning whether anything changed.
+// Only the lines of what changed are recreated, so that the entry, like the rest of the document, is written back the way it was read.
+func (d *Document) UpdateEntry(key EntryKey, update EntryUpdate) (bool, error) {
+	i, ok := d.findEntryIndex(key)
+	if !ok {
+		return false, EntryUpdateError{key, "Entry not found."}
+	}
+	entry := d.Entries[i]
+
+	if update.Value != nil && entry.IsPlural {
+		return false, EntryUpdateError{key, "Plural entries need plural values instead of a value."}
+	}
+	if update.PluralValues != nil && !entry.IsPlural {
+		return false, EntryUpdateError{key, "Only plural entries can have plural values."}
+	}
+	if update.PluralValues != nil && len(update.PluralValues) != len(entry.PluralValues) {
+		return false, EntryPluralCountError{entry.EntryKey, len(entry.PluralValues), len(update.PluralValues)}
+	}
+
+	value, pluralValues, header := entry.Value, entry.PluralValues, entry.Header
+	valuesChanged, headerChanged := false, false
+	if update.Value != nil && *update.Value != value {
+		value, valuesChanged = *update.Value, true
+	}
+	if update.PluralValues != nil && !slices.Equal(update.PluralValues, pluralValues) {
+		pluralValues, valuesChanged = slices.Clone(update.PluralValues), true
+	}
+	if update.Header != nil && !headersEqual(update.Header, &header) {
+		header, headerChanged = *update.Header, true
+	}
+	if !valuesChanged && !headerChanged {
+		return false, nil
+	}
+
+	updated := NewEntry(entry.EntryKey, header, value, pluralValues)
+	firstKeyword := slices.IndexFunc(entry.Lines, func(l Line) bool { return !l.IsCommentOrWhiteSpace })
+	if firstKeyword < 0 {
+		return false, EntryUpdateError{key, "Entry has no keyworded lines."}
+	}
+
+	commentLines := entry.Lines[:firstKeyword]
+	if headerChanged {
+		// blank lines and previous msgids, like "#| msgid", aren't part of the header, so are kept
+		var kept, previous []Line
+		for _, l := range commentLines {
+			if l.IsWhiteSpace {
+				kept = append(kept, l)
+			} else if strings.HasPrefix(l.Comment.Comment, "|") {
+				previous = append(previous, l)
+			}
+		}
+		commentLines = append(append(kept, header.lines()...), previous...)
+	}
+
+	keywordLines := entry.Lines[firstKeyword:]
+	if valuesChanged {
+		// skipping the blank line and header lines of the new entry
+		keywordLines = updated.Lines[1+len(header.lines()):]
+	}
+
+	updated.Lines = append(slices.Clone(commentLines), keywordLines...)
+	d.Entries[i] = updated
+	return true, nil
+}
+
+func headersEqual(a *EntryHeader, b *EntryHeader) bool {
+	return slices.Equal(a.TranslatorComments, b.TranslatorComments) &&
+		slices.Equal(a.ExtractedComments, b.ExtractedComments) &&
+		slices.Equal(a.References, b.References) &&
+		slices.Equal(a.Flags, b.Flags)
+}
@@ -41,9 +41,9 @@ func CompleteLine(keyword Keyword, lineValue LineValue, comment Comment) Line {
 	}
 
 	if !lineValue.IsEmpty {
-		rawValueBuilder.Grow(2 + len(lineValue.Value))
+		rawValueBuilder.Grow(2 + len(lineValue.Raw))
 		rawValueBuilder.WriteRune('"')
-		rawValueBuilder.WriteString(lineValue.Value)
+		rawValueBuilder.WriteString(lineValue.Raw)
 		rawValueBuilder.WriteRune('"')
 
 		if !comment.IsEmpty {
@@ -83,7 +83,7 @@ var parseRegex = regexp.MustCompile(`` +
 	`` + `\s*(?:\[(?<index>\d+)\])?` +
 	`)?` +
 	`\s*(?:"` + // value
-	`` + `(?<value>(?:[^"]|\\")*)` +
+	`` + `(?<value>(?:[^"\\]|\\.)*)` + // escape sequences are matched whole, so that \" and \\ don't end the value early
 	`")?` +
 	`\s*(?:` + // comment
 	`` + `#(?<comment>.*)` +
@@ -157,7 +157,7 @@ func (line *Line) populateExtraBools() *Line {
 	line.IsCommentOrWhiteSpace = line.Keyword.IsEmpty && line.Value.IsEmpty
 	line.IsWhiteSpace = line.IsCommentOrWhiteSpace && line.Comment.IsEmpty
 	line.IsComment = line.IsCommentOrWhiteSpace && !line.Comment.IsEmpty
-	line.IsMarkedObsolete = line.IsComment && line.Comment.Comment[0] == '~'
+	line.IsMarkedObsolete = line.IsComment && strings.HasPrefix(line.Comment.Comment, "~")
 
 	return line
 }
@@ -8,7 +8,7 @@ import (
 
 var (
 	rawStringParser   = regexp.MustCompile(`\\(a|b|e|f|n|r|t|v|\\|'|"|\?|[0-7]{3}|x[0-9a-f]{2}|.)`)
-	valueStringParser = regexp.MustCompile("\a|\b|\x1b|\f|\n|\r|\t|\v|\\|\"|[\x00-\x1f]")
+	valueStringParser = regexp.MustCompile("\a|\b|\x1b|\f|\n|\r|\t|\v|\\\\|\"|[\x00-\x1f]")
 )
 
 type LineValue struct {
@@ -0,0 +1,446 @@
+package gettext
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// PluralForms is gettext's own Plural-Forms header, such as "nplurals=2; plural=(n != 1);",
+// where the C expression picks n of msgstr[n]. Unlike plural rules, it only works with non-negative integers.
+type PluralForms struct {
+	NPlurals   int
+	Expression string
+
+	expression pluralFormsExpression
+}
+
+type pluralFormsExpression func(n uint64) uint64
+
+type PluralFormsParseError struct {
+	Value  string
+	Reason string
+}
+
+func (e PluralFormsParseError) Error() string {
+	return fmt.Sprintf("Failed to parse plural forms '%v': %v", e.Value, e.Reason)
+}
+
+// PluralFormsMismatch is a number where the plural forms and plural rules pick different msgstr[n].
+type PluralFormsMismatch struct {
+	N          uint64
+	FormsIndex int
+	RulesIndex int
+	RulesType  PluralType
+}
+
+type PluralFormsMismatchError struct {
+	NPlurals int
+	// ExpectedNPlurals is the number of msgstr[n] that integers reach, which can be fewer than the plural rules have,
+	// like with russian, whose other category is only for decimals.
+	ExpectedNPlurals int
+	Mismatches       []PluralFormsMismatch
+}
+
+func (e PluralFormsMismatchError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("Plural forms disagree with the plural rules.")
+	if e.NPlurals != e.ExpectedNPlurals {
+		fmt.Fprintf(&sb, " Expected nplurals=%v, but found nplurals=%v.", e.ExpectedNPlurals, e.NPlurals)
+	}
+	for _, m := range e.Mismatches {
+		fmt.Fprintf(&sb, " For %v, plural forms picked %v, but plural rules picked %v (%v).", m.N, m.FormsIndex, m.RulesIndex, m.RulesType)
+	}
+	return sb.String()
+}
+
+func ParsePluralForms(s string) (result PluralForms, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = PluralFormsParseError{s, fmt.Sprint(r)}
+		}
+	}()
+
+	matches := pluralFormsParser.FindStringSubmatch(s)
+	if matches == nil {
+		return PluralForms{}, PluralFormsParseError{s, "Expected the form 'nplurals=N; plural=EXPRESSION;'."}
+	}
+
+	nplurals, err := strconv.Atoi(matches[1])
+	if err != nil || nplurals < 1 {
+		return PluralForms{}, PluralFormsParseError{s, fmt.Sprint("Invalid nplurals: ", matches[1])}
+	}
+
+	expression := strings.TrimSpace(matches[2])
+	tokens := tokenizePluralForms(expression)
+	compiled := parsePluralFormsTernary(&tokens)
+	if len(tokens) > 0 {
+		panic(fmt.Sprint("Unexpectedly have additional tokens: ", tokens))
+	}
+
+	return PluralForms{nplurals, expression, compiled}, nil
+}
+
+// Evaluate returns n of msgstr[n], as calculated by the expression.
+// gettext itself falls back to msgstr[0] when the expression gives something at or past nplurals, but that is left to the caller.
+func (f *PluralForms) Evaluate(n uint64) int {
+	if f.expression == nil {
+		return 0
+	}
+	return int(f.expression(n))
+}
+
+// String formats the plural forms as they would appear in a header, without the "Plural-Forms: " field name.
+func (f *PluralForms) String() string {
+	return fmt.Sprintf("nplurals=%v; plural=%v;", f.NPlurals, f.Expression)
+}
+
+// Compare checks the plural forms against the plural rules over a broad range of integers, as well as the rules' own integer samples,
+// returning a PluralFormsMismatchError listing every number where they pick a different msgstr[n].
+func (f *PluralForms) Compare(rules *PluralRules) error {
+	result := PluralFormsMismatchError{NPlurals: f.NPlurals, ExpectedNPlurals: integerNPlurals(rules)}
+
+	for _, n := range pluralFormsSampleSpace(rules) {
+		pluralType := rules.Evaluate(decimal.NewFromUint64(n))
+		rulesIndex, _ := rules.Index(pluralType)
+		if formsIndex := f.Evaluate(n); formsIndex != rulesIndex {
+			result.Mismatches = append(result.Mismatches, PluralFormsMismatch{n, formsIndex, rulesIndex, pluralType})
+		}
+	}
+
+	if result.NPlurals != result.ExpectedNPlurals || len(result.Mismatches) > 0 {
+		return result
+	}
+	return nil
+}
+
+// the largest integer of the range that is always compared, which covers the usual % 10 and % 100 rules,
+// as well as the hundreds that some languages care about
+const pluralFormsSampleMax = 1099
+
+func pluralFormsSampleSpace(rules *PluralRules) []uint64 {
+	found := make(map[uint64]bool)
+	var result []uint64
+	add := func(n uint64) {
+		if !found[n] {
+			found[n] = true
+			result = append(result, n)
+		}
+	}
+
+	for n := uint64(0); n <= pluralFormsSampleMax; n++ {
+		add(n)
+	}
+	for n := uint64(10000); n <= 1000000000; n *= 10 {
+		add(n)
+		add(n + 1)
+	}
+
+	// some rules, like those for large numbers, are only seen in their samples
+	for _, samples := range rules.Samples() {
+		for _, sample := range samples.Integers {
+			if d := sample.Decimal(); d.IsInteger() && d.BigInt().IsUint64() {
+				add(d.BigInt().Uint64())
+			}
+		}
+	}
+
+	return result
+}
+
+var pluralFormsParser = regexp.MustCompile(`^\s*nplurals\s*=\s*([0-9]+)\s*;\s*plural\s*=\s*([^;]+);?\s*$`)
+
+// operators are listed from lowest to highest precedence, except for the ternary and unary operators, which are handled separately
+var pluralFormsBinaryOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", ">", "<=", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func tokenizePluralForms(expression string) []string {
+	var tokens []string
+	for i := 0; i < len(expression); {
+		ch := rune(expression[i])
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case unicode.IsDigit(ch):
+			start := i
+			for i < len(expression) && unicode.IsDigit(rune(expression[i])) {
+				i++
+			}
+			tokens = append(tokens, expression[start:i])
+		case ch == 'n':
+			tokens = append(tokens, "n")
+			i++
+		default:
+			if i+1 < len(expression) {
+				switch op := expression[i : i+2]; op {
+				case "||", "&&", "==", "!=", "<=", ">=":
+					tokens = append(tokens, op)
+					i += 2
+					continue
+				}
+			}
+
+			if !strings.ContainsRune("<>+-*/%!?:()", ch) {
+				panic(fmt.Sprintf("Unknown character '%c'", ch))
+			}
+			tokens = append(tokens, string(ch))
+			i++
+		}
+	}
+	return tokens
+}
+
+func readPluralFormsToken(tokens *[]string, expected ...string) (string, bool) {
+	if len(*tokens) == 0 {
+		return "", false
+	}
+
+	token := (*tokens)[0]
+	for _, e := range expected {
+		if token == e {
+			*tokens = (*tokens)[1:]
+			return token, true
+		}
+	}
+	return "", false
+}
+
+func parsePluralFormsTernary(tokens *[]string) pluralFormsExpression {
+	condition := parsePluralFormsBinary(tokens, 0)
+	if _, ok := readPluralFormsToken(tokens, "?"); !ok {
+		return condition
+	}
+
+	whenTrue := parsePluralFormsTernary(tokens)
+	if _, ok := readPluralFormsToken(tokens, ":"); !ok {
+		panic("Expected ':' for '?'.")
+	}
+	whenFalse := parsePluralFormsTernary(tokens)
+
+	return func(n uint64) uint64 {
+		if condition(n) != 0 {
+			return whenTrue(n)
+		}
+		return whenFalse(n)
+	}
+}
+
+func parsePluralFormsBinary(tokens *[]string, precedence int) pluralFormsExpression {
+	if precedence == len(pluralFormsBinaryOperators) {
+		return parsePluralFormsUnary(tokens)
+	}
+
+	left := parsePluralFormsBinary(tokens, precedence+1)
+	for {
+		op, ok := readPluralFormsToken(tokens, pluralFormsBinaryOperators[precedence]...)
+		if !ok {
+			return left
+		}
+		left = createPluralFormsBinaryOperation(op, left, parsePluralFormsBinary(tokens, precedence+1))
+	}
+}
+
+func createPluralFormsBinaryOperation(op string, left, right pluralFormsExpression) pluralFormsExpression {
+	fromBool := func(b bool) uint64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	switch op {
+	case "||":
+		return func(n uint64) uint64 { return fromBool(left(n) != 0 || right(n) != 0) }
+	case "&&":
+		return func(n uint64) uint64 { return fromBool(left(n) != 0 && right(n) != 0) }
+	case "==":
+		return func(n uint64) uint64 { return fromBool(left(n) == right(n)) }
+	case "!=":
+		return func(n uint64) uint64 { return fromBool(left(n) != right(n)) }
+	case "<":
+		return func(n uint64) uint64 { return fromBool(left(n) < right(n)) }
+	case ">":
+		return func(n uint64) uint64 { return fromBool(left(n) > right(n)) }
+	case "<=":
+		return func(n uint64) uint64 { return fromBool(left(n) <= right(n)) }
+	case ">=":
+		return func(n uint64) uint64 { return fromBool(left(n) >= right(n)) }
+	case "+":
+		return func(n uint64) uint64 { return left(n) + right(n) }
+	case "-":
+		return func(n uint64) uint64 { return left(n) - right(n) }
+	case "*":
+		return func(n uint64) uint64 { return left(n) * right(n) }
+	case "/":
+		// gettext's own evaluator gives 0 when dividing by zero, instead of crashing, so we do the same
+		return func(n uint64) uint64 {
+			if r := right(n); r != 0 {
+				return left(n) / r
+			}
+			return 0
+		}
+	case "%":
+		return func(n uint64) uint64 {
+			if r := right(n); r != 0 {
+				return left(n) % r
+			}
+			return 0
+		}
+	}
+	panic(fmt.Sprint("Unknown operator: ", op))
+}
+
+func parsePluralFormsUnary(tokens *[]string) pluralFormsExpression {
+	if _, ok := readPluralFormsToken(tokens, "!"); ok {
+		operand := parsePluralFormsUnary(tokens)
+		return func(n uint64) uint64 {
+			if operand(n) == 0 {
+				return 1
+			}
+			return 0
+		}
+	}
+
+	if _, ok := readPluralFormsToken(tokens, "("); ok {
+		result := parsePluralFormsTernary(tokens)
+		if _, ok := readPluralFormsToken(tokens, ")"); !ok {
+			panic("Expected ')'.")
+		}
+		return result
+	}
+
+	if len(*tokens) == 0 {
+		panic("Unexpected end of expression.")
+	}
+
+	token := (*tokens)[0]
+	*tokens = (*tokens)[1:]
+	if token == "n" {
+		return func(n uint64) uint64 { return n }
+	}
+
+	value, err := strconv.ParseUint(token, 10, 64)
+	if err != nil {
+		panic(fmt.Sprint("Unexpected token: ", token))
+	}
+	return func(uint64) uint64 { return value }
+}
+
+// PluralForms converts the rules into a Plural-Forms, such as for the header of a new document,
+// where msgstr[n] follows the order of the plural types up to the last one that integers reach, like PluralMapping does without a Plural-Forms.
+func (p *PluralRules) PluralForms() PluralForms {
+	nplurals := integerNPlurals(p)
+	isReached := make([]bool, nplurals)
+	for _, n := range pluralFormsSampleSpace(p) {
+		isReached[p.EvaluateIndex(decimal.NewFromUint64(n))] = true
+	}
+	var indexes []int
+	for i, reached := range isReached {
+		if reached {
+			indexes = append(indexes, i)
+		}
+	}
+
+	// integers that none of the other conditions match are left to the last plural type they reach
+	categories := p.Categories()
+	expression := strconv.Itoa(indexes[len(indexes)-1])
+	for j := len(indexes) - 2; j >= 0; j-- {
+		condition, _, _ := pluralFormsRuleCondition(p.rules[categories[indexes[j]]])
+		expression = fmt.Sprintf("%v ? %v : %v", condition, indexes[j], expression)
+	}
+	if len(indexes) > 1 {
+		expression = "(" + expression + ")"
+	}
+
+	forms, err := ParsePluralForms(fmt.Sprintf("nplurals=%v; plural=%v;", nplurals, expression))
+	if err != nil {
+		panic(fmt.Sprint("Unable to parse the converted plural forms: ", err))
+	}
+	return forms
+}
+
+// the conditions of plural forms only have n, which is always an integer, so i is the same as n,
+// and the operands for fractional digits and exponents are always 0, making relations on them either always or never met.
+// these return the condition, or, for conditions that are always or never met, whether they are met.
+
+func pluralFormsRuleCondition(rule *PluralRule) (condition string, isConstant bool, isMet bool) {
+	if rule == nil || len(rule.Conditions) == 0 {
+		return "", true, true
+	}
+
+	var conditions []string
+	for _, c := range rule.Conditions {
+		condition, isConstant, isMet := pluralFormsAndCondition(c)
+		if isConstant && isMet {
+			return "", true, true
+		}
+		if !isConstant {
+			conditions = append(conditions, condition)
+		}
+	}
+	if len(conditions) == 0 {
+		return "", true, false
+	}
+	return strings.Join(conditions, " || "), false, false
+}
+
+func pluralFormsAndCondition(c PluralRuleAndCondition) (condition string, isConstant bool, isMet bool) {
+	var conditions []string
+	for _, r := range c.Relations {
+		condition, isConstant, isMet := pluralFormsRelation(r)
+		if isConstant && !isMet {
+			return "", true, false
+		}
+		if !isConstant {
+			conditions = append(conditions, condition)
+		}
+	}
+	if len(conditions) == 0 {
+		return "", true, true
+	}
+	return strings.Join(conditions, " && "), false, false
+}
+
+func pluralFormsRelation(r PluralRuleRelation) (condition string, isConstant bool, isMet bool) {
+	if r.Operand != PluralOperandN && r.Operand != PluralOperandI {
+		return "", true, r.compile()(createOperands(decimal.Zero))
+	}
+
+	operand := "n"
+	if !r.Modulus.IsZero() {
+		operand = fmt.Sprint("n%", r.Modulus)
+	}
+
+	conditions := make([]string, len(r.Ranges))
+	for i, rr := range r.Ranges {
+		switch {
+		case rr.Low.Equal(rr.High) && r.IsNegated:
+			conditions[i] = fmt.Sprint(operand, "!=", rr.Low)
+		case rr.Low.Equal(rr.High):
+			conditions[i] = fmt.Sprint(operand, "==", rr.Low)
+		case r.IsNegated:
+			conditions[i] = fmt.Sprint("(", operand, "<", rr.Low, " || ", operand, ">", rr.High, ")")
+		default:
+			conditions[i] = fmt.Sprint(operand, ">=", rr.Low, " && ", operand, "<=", rr.High)
+		}
+	}
+
+	// x != 4,6 is neither, and x = 4,6 is either, which needs parentheses to be and-ed with other relations
+	if r.IsNegated {
+		return strings.Join(conditions, " && "), false, false
+	}
+	if len(conditions) > 1 {
+		return "(" + strings.Join(conditions, " || ") + ")", false, false
+	}
+	return conditions[0], false, false
+}
@@ -0,0 +1,156 @@
+package gettext
+
+import (
+	"slices"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/language"
+)
+
+// PluralMapping maps the plural types of a document to the msgstr[n] of its plural entries, which converters need
+// for formats that key plural forms by category instead.
+// With a Plural-Forms header, msgstr[n] is whatever its expression picks, so a russian document with the usual nplurals=3
+// has msgstr[0], msgstr[1], and msgstr[2] for one, few, and many.
+// Otherwise, msgstr[n] follows the order of the plural types, up to the last one that integers reach,
+// since gettext only picks plural forms for integers.
+type PluralMapping struct {
+	Rules PluralRules
+	// NPlurals is the number of msgstr[n] each plural entry has.
+	NPlurals int
+
+	indexes [PluralTypeOther + 1]int
+	// the plural type each msgstr[n] is for
+	pluralTypes []PluralType
+}
+
+// PluralMapping creates the mapping for the plural rules that PluralRulesOrDefault returns and the header's Plural-Forms, if any.
+// Malformed Plural-Forms return a PluralFormsParseError.
+func (h *DocumentHeader) PluralMapping() (PluralMapping, error) {
+	rules, err := h.PluralRulesOrDefault()
+	if err != nil {
+		return PluralMapping{}, err
+	}
+	if len(h.PluralForms) == 0 {
+		return NewPluralMapping(&rules, nil), nil
+	}
+
+	forms, err := ParsePluralForms(h.PluralForms)
+	if err != nil {
+		return PluralMapping{}, err
+	}
+	return NewPluralMapping(&rules, &forms), nil
+}
+
+// DefaultPluralMapping is the mapping of documents that NewDocument creates for the language,
+// which converters use to create the plural entries of such documents.
+func DefaultPluralMapping(tag language.Tag) (PluralMapping, error) {
+	definition, err := GetDefaultPluralRulesDefinition(tag.String())
+	if err != nil {
+		return PluralMapping{}, err
+	}
+	rules := definition.Parse()
+	return NewPluralMapping(&rules, nil), nil
+}
+
+// NewPluralMapping creates the mapping for the rules, where the plural forms, which may be nil, pick the msgstr[n].
+func NewPluralMapping(rules *PluralRules, forms *PluralForms) PluralMapping {
+	m := PluralMapping{Rules: *rules, NPlurals: integerNPlurals(rules)}
+	index := func(n uint64) int {
+		return rules.EvaluateIndex(decimal.NewFromUint64(n))
+	}
+	if forms != nil {
+		m.NPlurals = forms.NPlurals
+		index = func(n uint64) int {
+			// like gettext, which uses msgstr[0] when the expression picks a msgstr[n] that isn't there
+			if i := forms.Evaluate(n); i < forms.NPlurals {
+				return i
+			}
+			return 0
+		}
+	}
+
+	samples := rules.Samples()
+	for _, pluralType := range rules.Categories() {
+		m.indexes[pluralType] = index(firstSample(samples[pluralType]))
+	}
+
+	// each msgstr[n] is for the plural type of the first integer that picks it, where those no integer picks are left as other
+	m.pluralTypes = make([]PluralType, m.NPlurals)
+	found := make([]bool, m.NPlurals)
+	for i := range m.pluralTypes {
+		m.pluralTypes[i] = PluralTypeOther
+	}
+	for _, n := range pluralFormsSampleSpace(rules) {
+		if i := index(n); !found[i] {
+			found[i] = true
+			m.pluralTypes[i] = rules.Evaluate(decimal.NewFromUint64(n))
+		}
+	}
+
+	return m
+}
+
+// integerNPlurals is the number of msgstr[n] that integers reach, following the order of the plural types
+func integerNPlurals(rules *PluralRules) int {
+	result := 0
+	for _, n := range pluralFormsSampleSpace(rules) {
+		result = max(result, rules.EvaluateIndex(decimal.NewFromUint64(n))+1)
+	}
+	return result
+}
+
+// firstSample is the integer to pick the msgstr[n] of a plural type with, where plural types only used for decimals,
+// like russian's other, use the integer part of a decimal, which is what ngettext sees for them
+func firstSample(samples PluralSamples) uint64 {
+	for _, sample := range slices.Concat(samples.Integers, samples.Decimals) {
+		if d := sample.Decimal().Truncate(0); !d.IsNegative() && d.BigInt().IsUint64() {
+			return d.BigInt().Uint64()
+		}
+	}
+	return 0
+}
+
+// Categories returns the plural types of the rules; see PluralRules.Categories.
+func (m *PluralMapping) Categories() []PluralType {
+	return m.Rules.Categories()
+}
+
+// Index returns n of the msgstr[n] used for the plural type, or false if the rules don't use the plural type.
+// Plural types can share a msgstr[n], like russian's many and other.
+func (m *PluralMapping) Index(t PluralType) (int, bool) {
+	if _, ok := m.Rules.Index(t); !ok {
+		return 0, false
+	}
+	return m.indexes[t], true
+}
+
+// PluralType returns the plural type that msgstr[n] is for, which is the one of the integers that use it.
+func (m *PluralMapping) PluralType(index int) PluralType {
+	return m.pluralTypes[index]
+}
+
+// Value returns the msgstr[n] for the plural type, or an empty string if the plural values don't have it.
+func (m *PluralMapping) Value(pluralValues []string, t PluralType) string {
+	if i, ok := m.Index(t); ok && i < len(pluralValues) {
+		return pluralValues[i]
+	}
+	return ""
+}
+
+// PluralValues creates the msgstr[n] of a plural entry from the value of each plural type,
+// such as for formats that key plural forms by category.
+func (m *PluralMapping) PluralValues(value func(PluralType) string) []string {
+	result := make([]string, m.NPlurals)
+	for i, pluralType := range m.pluralTypes {
+		result[i] = value(pluralType)
+	}
+	return result
+}
+
+// ValidatePluralCount checks that a plural entry has NPlurals msgstr[n]. Entries that aren't plural are always valid.
+func (m *PluralMapping) ValidatePluralCount(e *Entry) error {
+	if e.IsPlural && len(e.PluralValues) != m.NPlurals {
+		return EntryPluralCountError{e.EntryKey, m.NPlurals, len(e.PluralValues)}
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package gettext
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/unicode/cldr"
+)
+
+type PluralRange struct {
+	Start, End, Result PluralType
+}
+
+type pluralRangeKey struct {
+	start, end PluralType
+}
+
+// EvaluateRange returns the plural type of a range of numbers, such as "1–3 items".
+// The plural types of the start and end are looked up in the ranges of the rules, and,
+// like Evaluate, PluralTypeOther is returned for ranges the rules do not contain.
+func (p *PluralRules) EvaluateRange(start, end decimal.Decimal) PluralType {
+	key := pluralRangeKey{p.Evaluate(start), p.Evaluate(end)}
+	if result, ok := p.ranges[key]; ok {
+		return result
+	}
+
+	return PluralTypeOther
+}
+
+func createPluralRanges(ranges []PluralRange) map[pluralRangeKey]PluralType {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	result := make(map[pluralRangeKey]PluralType, len(ranges))
+	for _, r := range ranges {
+		result[pluralRangeKey{r.Start, r.End}] = r.Result
+	}
+	return result
+}
+
+func loadPluralRanges(data *cldr.CLDR) map[string][]PluralRange {
+	supplemental := data.Supplemental()
+	result := make(map[string][]PluralRange)
+
+	for _, plural := range supplemental.Plurals {
+		for _, pluralRanges := range plural.PluralRanges {
+			ranges := make([]PluralRange, 0, len(pluralRanges.PluralRange))
+			for _, pluralRange := range pluralRanges.PluralRange {
+				ranges = append(ranges, PluralRange{
+					Start:  mustGetPluralTypeFromCategory(pluralRange.Start),
+					End:    mustGetPluralTypeFromCategory(pluralRange.End),
+					Result: mustGetPluralTypeFromCategory(pluralRange.Result),
+				})
+			}
+
+			for _, l := range strings.Split(pluralRanges.Locales, " ") {
+				result[l] = ranges
+			}
+		}
+	}
+
+	return result
+}
+
+func mustGetPluralTypeFromCategory(category string) PluralType {
+	pluralType, ok := PluralTypeFromCategory(category)
+	if !ok {
+		panic(fmt.Sprint("Unknown plural category: ", category))
+	}
+	return pluralType
+}
+
+const sourcePluralRanges = `
+<?xml version="1.0" encoding="UTF-8" ?>
+<!DOCTYPE supplementalData SYSTEM "../../common/dtd/ldmlSupplemental.dtd">
+<!--
+Copyright © 1991-2022 Unicode, Inc.
+For terms of use, see http://www.unicode.org/copyright.html
+SPDX-License-Identifier: Unicode-3.0
+CLDR data files are interpreted according to the LDML specification (http://unicode.org/reports/tr35/)
+-->
+<supplementalData>
+    <version number="$Revision$"/>
+    <plurals>
+        <pluralRanges locales="id ja km ko lo ms my th vi zh">
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="am bn fr gu hi hy kn mr pa zu">
+            <pluralRange start="one" end="one" result="one"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="fa">
+            <pluralRange start="one" end="one" result="other"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="ka">
+            <pluralRange start="one" end="other" result="one"/>
+            <pluralRange start="other" end="one" result="other"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="az de el gl hu it kk ky ml mn ne nl pt sq sw ta te tr ug uz">
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="one"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="af bg ca en es et eu fi nb sv ur">
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="other"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="da fil is">
+            <pluralRange start="one" end="one" result="one"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="one"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="si">
+            <pluralRange start="one" end="one" result="one"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="other"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="mk">
+            <pluralRange start="one" end="one" result="other"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="other"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="lv">
+            <pluralRange start="zero" end="zero" result="other"/>
+            <pluralRange start="zero" end="one" result="one"/>
+            <pluralRange start="zero" end="other" result="other"/>
+            <pluralRange start="one" end="zero" result="other"/>
+            <pluralRange start="one" end="one" result="one"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="other" end="zero" result="other"/>
+            <pluralRange start="other" end="one" result="one"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="ro">
+            <pluralRange start="one" end="few" result="few"/>
+            <pluralRange start="few" end="one" result="few"/>
+            <pluralRange start="few" end="few" result="few"/>
+            <pluralRange start="few" end="other" result="other"/>
+            <pluralRange start="other" end="few" result="few"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="bs hr sr">
+            <pluralRange start="one" end="one" result="one"/>
+            <pluralRange start="one" end="few" result="few"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="few" end="one" result="one"/>
+            <pluralRange start="few" end="few" result="few"/>
+            <pluralRange start="few" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="one"/>
+            <pluralRange start="other" end="few" result="few"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="sl">
+            <pluralRange start="one" end="one" result="few"/>
+            <pluralRange start="one" end="two" result="two"/>
+            <pluralRange start="one" end="few" result="few"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="two" end="one" result="few"/>
+            <pluralRange start="two" end="two" result="two"/>
+            <pluralRange start="two" end="few" result="few"/>
+            <pluralRange start="two" end="other" result="other"/>
+            <pluralRange start="few" end="one" result="few"/>
+            <pluralRange start="few" end="two" result="two"/>
+            <pluralRange start="few" end="few" result="few"/>
+            <pluralRange start="few" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="few"/>
+            <pluralRange start="other" end="two" result="two"/>
+            <pluralRange start="other" end="few" result="few"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="he">
+            <pluralRange start="one" end="two" result="other"/>
+            <pluralRange start="one" end="many" result="many"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="two" end="many" result="other"/>
+            <pluralRange start="two" end="other" result="other"/>
+            <pluralRange start="many" end="many" result="many"/>
+            <pluralRange start="many" end="other" result="many"/>
+            <pluralRange start="other" end="one" result="other"/>
+            <pluralRange start="other" end="two" result="other"/>
+            <pluralRange start="other" end="many" result="many"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="cs pl sk">
+            <pluralRange start="one" end="few" result="few"/>
+            <pluralRange start="one" end="many" result="many"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="few" end="few" result="few"/>
+            <pluralRange start="few" end="many" result="many"/>
+            <pluralRange start="few" end="other" result="other"/>
+            <pluralRange start="many" end="one" result="one"/>
+            <pluralRange start="many" end="few" result="few"/>
+            <pluralRange start="many" end="many" result="many"/>
+            <pluralRange start="many" end="other" result="many"/>
+            <pluralRange start="other" end="one" result="one"/>
+            <pluralRange start="other" end="few" result="few"/>
+            <pluralRange start="other" end="many" result="many"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="lt ru uk">
+            <pluralRange start="one" end="one" result="one"/>
+            <pluralRange start="one" end="few" result="few"/>
+            <pluralRange start="one" end="many" result="many"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="few" end="one" result="one"/>
+            <pluralRange start="few" end="few" result="few"/>
+            <pluralRange start="few" end="many" result="many"/>
+            <pluralRange start="few" end="other" result="other"/>
+            <pluralRange start="many" end="one" result="one"/>
+            <pluralRange start="many" end="few" result="few"/>
+            <pluralRange start="many" end="many" result="many"/>
+            <pluralRange start="many" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="one"/>
+            <pluralRange start="other" end="few" result="few"/>
+            <pluralRange start="other" end="many" result="many"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="cy">
+            <pluralRange start="zero" end="one" result="one"/>
+            <pluralRange start="zero" end="two" result="two"/>
+            <pluralRange start="zero" end="few" result="few"/>
+            <pluralRange start="zero" end="many" result="many"/>
+            <pluralRange start="zero" end="other" result="other"/>
+            <pluralRange start="one" end="two" result="two"/>
+            <pluralRange start="one" end="few" result="few"/>
+            <pluralRange start="one" end="many" result="many"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="two" end="few" result="few"/>
+            <pluralRange start="two" end="many" result="many"/>
+            <pluralRange start="two" end="other" result="other"/>
+            <pluralRange start="few" end="many" result="many"/>
+            <pluralRange start="few" end="other" result="other"/>
+            <pluralRange start="many" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="one"/>
+            <pluralRange start="other" end="two" result="two"/>
+            <pluralRange start="other" end="few" result="few"/>
+            <pluralRange start="other" end="many" result="many"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="ga">
+            <pluralRange start="one" end="two" result="two"/>
+            <pluralRange start="one" end="few" result="few"/>
+            <pluralRange start="one" end="many" result="many"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="two" end="few" result="few"/>
+            <pluralRange start="two" end="many" result="many"/>
+            <pluralRange start="two" end="other" result="other"/>
+            <pluralRange start="few" end="few" result="few"/>
+            <pluralRange start="few" end="many" result="many"/>
+            <pluralRange start="few" end="other" result="other"/>
+            <pluralRange start="many" end="many" result="many"/>
+            <pluralRange start="many" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="one"/>
+            <pluralRange start="other" end="two" result="two"/>
+            <pluralRange start="other" end="few" result="few"/>
+            <pluralRange start="other" end="many" result="many"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+        <pluralRanges locales="ar">
+            <pluralRange start="zero" end="one" result="zero"/>
+            <pluralRange start="zero" end="two" result="zero"/>
+            <pluralRange start="zero" end="few" result="few"/>
+            <pluralRange start="zero" end="many" result="many"/>
+            <pluralRange start="zero" end="other" result="other"/>
+            <pluralRange start="one" end="two" result="other"/>
+            <pluralRange start="one" end="few" result="few"/>
+            <pluralRange start="one" end="many" result="many"/>
+            <pluralRange start="one" end="other" result="other"/>
+            <pluralRange start="two" end="few" result="few"/>
+            <pluralRange start="two" end="many" result="many"/>
+            <pluralRange start="two" end="other" result="other"/>
+            <pluralRange start="few" end="few" result="few"/>
+            <pluralRange start="few" end="many" result="many"/>
+            <pluralRange start="few" end="other" result="other"/>
+            <pluralRange start="many" end="few" result="few"/>
+            <pluralRange start="many" end="many" result="many"/>
+            <pluralRange start="many" end="other" result="other"/>
+            <pluralRange start="other" end="one" result="other"/>
+            <pluralRange start="other" end="two" result="other"/>
+            <pluralRange start="other" end="few" result="few"/>
+            <pluralRange start="other" end="many" result="many"/>
+            <pluralRange start="other" end="other" result="other"/>
+        </pluralRanges>
+    </plurals>
+</supplementalData>
+`
@@ -0,0 +1,280 @@
+package gettext
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// PluralRule is the parsed form of a rule such as "n % 10 = 1 and n % 100 != 11 @integer 1, 21, 31, …".
+// The rule matches if any of its conditions match. A rule without conditions, like the usual other rule, always matches.
+type PluralRule struct {
+	Conditions []PluralRuleAndCondition
+	// the raw @integer and @decimal samples, if any
+	Samples string
+}
+
+// PluralRuleAndCondition matches if all of its relations match.
+type PluralRuleAndCondition struct {
+	Relations []PluralRuleRelation
+}
+
+// PluralRuleRelation is a relation such as "n % 100 != 11..19,30", matching if the operand is in any of the ranges,
+// or, if negated, not in any of them.
+type PluralRuleRelation struct {
+	Operand PluralOperand
+	// Modulus is zero when the relation has no modulus
+	Modulus   decimal.Decimal
+	IsNegated bool
+	Ranges    []PluralRuleRange
+}
+
+// PluralRuleRange is either a range such as 11..19, or a single value, where Low and High are the same.
+type PluralRuleRange struct {
+	Low, High decimal.Decimal
+}
+
+type PluralOperand string
+
+const (
+	PluralOperandN = PluralOperand("n")
+	PluralOperandI = PluralOperand("i")
+	PluralOperandV = PluralOperand("v")
+	PluralOperandW = PluralOperand("w")
+	PluralOperandF = PluralOperand("f")
+	PluralOperandT = PluralOperand("t")
+	PluralOperandC = PluralOperand("c")
+	PluralOperandE = PluralOperand("e")
+)
+
+// Operation converts the rule into the closure form, which doesn't need to walk the rule on each evaluation.
+func (r *PluralRule) Operation() PluralRuleOperation {
+	relation := r.compile()
+	return func(d decimal.Decimal) bool {
+		return relation(createOperands(d))
+	}
+}
+
+// Equal compares the conditions of the rules, ignoring their samples.
+func (r *PluralRule) Equal(other *PluralRule) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+
+	if len(r.Conditions) != len(other.Conditions) {
+		return false
+	}
+	for i, c := range r.Conditions {
+		if !c.Equal(other.Conditions[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c PluralRuleAndCondition) Equal(other PluralRuleAndCondition) bool {
+	if len(c.Relations) != len(other.Relations) {
+		return false
+	}
+	for i, r := range c.Relations {
+		if !r.Equal(other.Relations[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r PluralRuleRelation) Equal(other PluralRuleRelation) bool {
+	if r.Operand != other.Operand || !r.Modulus.Equal(other.Modulus) || r.IsNegated != other.IsNegated {
+		return false
+	}
+
+	if len(r.Ranges) != len(other.Ranges) {
+		return false
+	}
+	for i, rr := range r.Ranges {
+		if !rr.Low.Equal(other.Ranges[i].Low) || !rr.High.Equal(other.Ranges[i].High) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the rule in CLDR syntax, including the samples.
+func (r *PluralRule) String() string {
+	if len(r.Samples) == 0 {
+		return r.ConditionString()
+	}
+
+	// without conditions, this results in a leading space, which is also how the CLDR data writes such rules
+	return fmt.Sprint(r.ConditionString(), " ", r.Samples)
+}
+
+// ConditionString returns the rule in CLDR syntax, without the samples.
+func (r *PluralRule) ConditionString() string {
+	conditions := make([]string, len(r.Conditions))
+	for i, c := range r.Conditions {
+		conditions[i] = c.String()
+	}
+	return strings.Join(conditions, " or ")
+}
+
+func (c PluralRuleAndCondition) String() string {
+	relations := make([]string, len(c.Relations))
+	for i, r := range c.Relations {
+		relations[i] = r.String()
+	}
+	return strings.Join(relations, " and ")
+}
+
+func (r PluralRuleRelation) String() string {
+	var sb strings.Builder
+	sb.WriteString(string(r.Operand))
+
+	if !r.Modulus.IsZero() {
+		sb.WriteString(" % ")
+		sb.WriteString(r.Modulus.String())
+	}
+
+	if r.IsNegated {
+		sb.WriteString(" != ")
+	} else {
+		sb.WriteString(" = ")
+	}
+
+	for i, rr := range r.Ranges {
+		if i > 0 {
+			sb.WriteRune(',')
+		}
+		sb.WriteString(rr.String())
+	}
+
+	return sb.String()
+}
+
+func (r PluralRuleRange) String() string {
+	if r.Low.Equal(r.High) {
+		return r.Low.String()
+	}
+	return fmt.Sprint(r.Low, "..", r.High)
+}
+
+func (r *PluralRule) compile() relation {
+	if len(r.Conditions) == 0 {
+		return func(_ operands) bool {
+			return true
+		}
+	}
+
+	conditions := make([]relation, len(r.Conditions))
+	for i, c := range r.Conditions {
+		conditions[i] = c.compile()
+	}
+
+	return func(o operands) bool {
+		for _, c := range conditions {
+			if c(o) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c PluralRuleAndCondition) compile() relation {
+	relations := make([]relation, len(c.Relations))
+	for i, r := range c.Relations {
+		relations[i] = r.compile()
+	}
+
+	return func(o operands) bool {
+		for _, r := range relations {
+			if !r(o) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func (r PluralRuleRelation) compile() relation {
+	accessor := createAccessor(r.Operand, r.Modulus)
+
+	ranges := make([]relation, len(r.Ranges))
+	for i, rr := range r.Ranges {
+		ranges[i] = rr.compile(accessor)
+	}
+
+	isNegated := r.IsNegated
+	return func(o operands) bool {
+		// for x != 4,6,9, x must be none of the values, so the whole list is negated, rather than each value
+		for _, rr := range ranges {
+			if rr(o) {
+				return !isNegated
+			}
+		}
+		return isNegated
+	}
+}
+
+func (r PluralRuleRange) compile(accessor accessor) relation {
+	low, high := r.Low, r.High
+	if low.Equal(high) {
+		return func(o operands) bool {
+			return accessor(o).Equal(low)
+		}
+	}
+
+	return func(o operands) bool {
+		// ranges only contain integers, so n = 0..1 does not match 0.5
+		n := accessor(o)
+		return n.IsInteger() && n.GreaterThanOrEqual(low) && n.LessThanOrEqual(high)
+	}
+}
+
+type accessor func(o operands) decimal.Decimal
+
+var (
+	accessorN = func(o operands) decimal.Decimal { return o.N }
+	accessorI = func(o operands) decimal.Decimal { return o.I }
+	accessorV = func(o operands) decimal.Decimal { return o.V }
+	accessorW = func(o operands) decimal.Decimal { return o.W }
+	accessorF = func(o operands) decimal.Decimal { return o.F }
+	accessorT = func(o operands) decimal.Decimal { return o.T }
+	accessorC = func(o operands) decimal.Decimal { return o.C }
+	accessorE = func(o operands) decimal.Decimal { return o.E }
+)
+
+func createAccessor(operand PluralOperand, modulus decimal.Decimal) accessor {
+	var accessor accessor
+	switch operand {
+	case PluralOperandN:
+		accessor = accessorN
+	case PluralOperandI:
+		accessor = accessorI
+	case PluralOperandV:
+		accessor = accessorV
+	case PluralOperandW:
+		accessor = accessorW
+	case PluralOperandF:
+		accessor = accessorF
+	case PluralOperandT:
+		accessor = accessorT
+	case PluralOperandC:
+		accessor = accessorC
+	case PluralOperandE:
+		accessor = accessorE
+	default:
+		panic(fmt.Sprint("Unknown operand name: ", operand))
+	}
+
+	if !modulus.IsZero() {
+		oldAccessor := accessor
+		accessor = func(o operands) decimal.Decimal {
+			return oldAccessor(o).Mod(modulus)
+		}
+	}
+
+	return accessor
+}
@@ -13,7 +13,22 @@ type relation func(o operands) bool
 
 // when panicing, would be nice to output an informative string
 // but since these will generally be loaded from the official xml, panicing should not happen
-func parsePluralRule(pluralRule string) PluralRuleOperation {
+func parsePluralRule(pluralRule string) *PluralRule {
+	result := parsePluralRuleWithoutValidation(pluralRule)
+	if result == nil {
+		return nil
+	}
+
+	validationError := validatePluralRule(result.compile(), result.Samples, pluralRule)
+	if validationError != nil {
+		panic(validationError)
+	}
+
+	return result
+}
+
+// parsePluralRuleWithoutValidation leaves validating the rule against its samples up to the caller
+func parsePluralRuleWithoutValidation(pluralRule string) *PluralRule {
 	// NOTE: this means we dont support plural rules without the sample string
 	// since we can't differentiate between a zero-length (valid) rule and an non-existent rule
 	// but this should not be a problem in practice
@@ -22,21 +37,16 @@ func parsePluralRule(pluralRule string) PluralRuleOperation {
 	}
 
 	tokens, sample := tokenizePluralRule(pluralRule)
+	result := &PluralRule{Samples: strings.TrimSpace(sample)}
 	if len(*tokens) == 0 {
-		return func(_ decimal.Decimal) bool {
-			return true
-		}
+		return result
 	}
 
-	relation := constructAndConditionChain(tokens)
+	result.Conditions = append(result.Conditions, constructAndCondition(tokens))
 
 	kind, _ := readNextToken(tokens, tokenOr)
 	for kind != tokenNotFound {
-		oldRelation := relation
-		newRelation := constructAndConditionChain(tokens)
-		relation = func(o operands) bool {
-			return oldRelation(o) || newRelation(o)
-		}
+		result.Conditions = append(result.Conditions, constructAndCondition(tokens))
 
 		kind, _ = readNextToken(tokens, tokenOr)
 	}
@@ -45,57 +55,64 @@ func parsePluralRule(pluralRule string) PluralRuleOperation {
 		panic(fmt.Sprint("Unexpectedly have additional tokens: ", *tokens))
 	}
 
-	result := func(d decimal.Decimal) bool {
-		return relation(createOperands(d))
-	}
+	return result
+}
 
-	validationError := validatePluralRule(result, sample, pluralRule)
-	if validationError != nil {
-		panic(validationError)
-	}
+// tryParsePluralRule is for rules that don't come from the official xml, such as those found in headers,
+// where panicing on a malformed rule is not appropriate
+func tryParsePluralRule(pluralRule string) (result *PluralRule, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = PluralRuleParseError{pluralRule, fmt.Sprint(r)}
+		}
+	}()
 
-	return result
+	result = parsePluralRuleWithoutValidation(pluralRule)
+	return
+}
+
+type PluralRuleParseError struct {
+	RuleString string
+	Reason     string
+}
+
+func (e PluralRuleParseError) Error() string {
+	return fmt.Sprintf("Failed to parse plural rule '%v': %v", e.RuleString, e.Reason)
 }
 
-func constructAndConditionChain(tokens *[]token) relation {
-	relation := constructListRelation(tokens)
+func constructAndCondition(tokens *[]token) PluralRuleAndCondition {
+	var condition PluralRuleAndCondition
+	condition.Relations = append(condition.Relations, constructRelation(tokens))
 
 	kind, _ := readNextToken(tokens, tokenAnd)
 	for kind != tokenNotFound {
-		oldRelation := relation
-		newRelation := constructListRelation(tokens)
-		relation = func(o operands) bool {
-			return oldRelation(o) && newRelation(o)
-		}
+		condition.Relations = append(condition.Relations, constructRelation(tokens))
 
 		kind, _ = readNextToken(tokens, tokenAnd)
 	}
 
-	return relation
+	return condition
 }
 
-func constructListRelation(tokens *[]token) relation {
-	accessor := constructAccessor(tokens)
+func constructRelation(tokens *[]token) PluralRuleRelation {
+	var relation PluralRuleRelation
+	relation.Operand = readOperand(tokens)
+	relation.Modulus, _ = readModulus(tokens)
 
-	var isEqualityOperation bool
 	switch kind, _ := mustReadNextToken(tokens, tokenEquals, tokenNotEquals); kind {
 	case tokenEquals:
-		isEqualityOperation = true
+		relation.IsNegated = false
 	case tokenNotEquals:
-		isEqualityOperation = false
+		relation.IsNegated = true
 	default:
 		panic("Cannot be hit because we verify tokenEquals and tokenNotEquals")
 	}
 
-	relation := constructSingleRelation(tokens, accessor, isEqualityOperation)
+	relation.Ranges = append(relation.Ranges, constructRange(tokens))
 
 	kind, _ := readNextToken(tokens, tokenComma)
 	for kind != tokenNotFound {
-		oldRelation := relation
-		newRelation := constructSingleRelation(tokens, accessor, isEqualityOperation)
-		relation = func(o operands) bool {
-			return oldRelation(o) || newRelation(o)
-		}
+		relation.Ranges = append(relation.Ranges, constructRange(tokens))
 
 		kind, _ = readNextToken(tokens, tokenComma)
 	}
@@ -103,81 +120,27 @@ func constructListRelation(tokens *[]token) relation {
 	return relation
 }
 
-func constructSingleRelation(tokens *[]token, accessor accessor, isEqualityOperation bool) relation {
+func constructRange(tokens *[]token) PluralRuleRange {
 	number := readNumber(tokens)
 	highNumber, isRange := readRange(tokens)
 
-	var r relation
-	if isRange {
-		r = func(o operands) (result bool) {
-			n := accessor(o)
-			result = n.GreaterThanOrEqual(number) && n.LessThanOrEqual(highNumber)
-			if !isEqualityOperation {
-				result = !result
-			}
-			return
-		}
-	} else {
-		r = func(o operands) (result bool) {
-			n := accessor(o)
-			result = n.Equal(number)
-			if !isEqualityOperation {
-				result = !result
-			}
-			return
-		}
+	if !isRange {
+		return PluralRuleRange{number, number}
 	}
-	return r
+	return PluralRuleRange{number, highNumber}
 }
 
-type accessor func(o operands) decimal.Decimal
-
-var (
-	accessorN = func(o operands) decimal.Decimal { return o.N }
-	accessorI = func(o operands) decimal.Decimal { return o.I }
-	accessorV = func(o operands) decimal.Decimal { return o.V }
-	accessorW = func(o operands) decimal.Decimal { return o.W }
-	accessorF = func(o operands) decimal.Decimal { return o.F }
-	accessorT = func(o operands) decimal.Decimal { return o.T }
-	// not supported and can only ever be zero
-	accessorC = func(o operands) decimal.Decimal { return decimal.Zero }
-	accessorE = func(o operands) decimal.Decimal { return decimal.Zero }
-)
-
-func constructAccessor(tokens *[]token) accessor {
+func readOperand(tokens *[]token) PluralOperand {
 	_, operandName := mustReadNextToken(tokens, tokenOperandName)
-	modValue, hasModValue := readModulus(tokens)
-
-	var accessor accessor
-	switch operandName {
-	case "n":
-		accessor = accessorN
-	case "i":
-		accessor = accessorI
-	case "v":
-		accessor = accessorV
-	case "w":
-		accessor = accessorW
-	case "f":
-		accessor = accessorF
-	case "t":
-		accessor = accessorT
-	case "c":
-		accessor = accessorC
-	case "e":
-		accessor = accessorE
+
+	operand := PluralOperand(operandName)
+	switch operand {
+	case PluralOperandN, PluralOperandI, PluralOperandV, PluralOperandW,
+		PluralOperandF, PluralOperandT, PluralOperandC, PluralOperandE:
+		return operand
 	default:
 		panic(fmt.Sprint("Unknown operand name: ", operandName))
 	}
-
-	if hasModValue {
-		oldAccessor := accessor
-		accessor = func(o operands) decimal.Decimal {
-			return oldAccessor(o).Mod(modValue)
-		}
-	}
-
-	return accessor
 }
 
 type tokenKind int
@@ -11,7 +11,7 @@ import (
 
 type PluralRuleValidationError struct {
 	RuleString     string
-	InvalidSamples []decimal.Decimal
+	InvalidSamples []CompactDecimal
 }
 
 func (e PluralRuleValidationError) Error() string {
@@ -19,12 +19,130 @@ func (e PluralRuleValidationError) Error() string {
 		e.RuleString, e.InvalidSamples)
 }
 
-func validatePluralRule(pluralRule PluralRuleOperation, sampleString string, ruleString string) error {
+type PluralRulesOverlapError struct {
+	Sample      CompactDecimal
+	PluralTypes []PluralType
+}
+
+func (e PluralRulesOverlapError) Error() string {
+	return fmt.Sprintf("Sample '%v' is matched by multiple plural rules: %v", e.Sample, e.PluralTypes)
+}
+
+type PluralRulesUncaughtSampleError struct {
+	Sample CompactDecimal
+}
+
+func (e PluralRulesUncaughtSampleError) Error() string {
+	return fmt.Sprintf("Sample '%v' is not matched by any plural rule, including the other rule.", e.Sample)
+}
+
+type PluralRulesMissingOtherError struct{}
+
+func (e PluralRulesMissingOtherError) Error() string {
+	return "Plural rules must include an other rule to catch everything the remaining rules do not."
+}
+
+type PluralRulesDefinitionError struct {
+	Errors []error
+}
+
+func (e PluralRulesDefinitionError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprint("Plural rules failed validation: ", strings.Join(messages, " "))
+}
+
+// Validate checks each rule against its own @integer and @decimal samples, like the default rules are checked.
+// Since rules from elsewhere, like headers, are less trustworthy, it also checks every sample against every rule,
+// making sure that no two rules match the same sample and that the other rule catches everything else.
+func (d *PluralRulesDefinition) Validate() error {
+	type typedSample struct {
+		pluralType PluralType
+		sample     operands
+		raw        CompactDecimal
+	}
+
+	if d.ruleStrings() == [PluralTypeOther + 1]string{} {
+		// no rules at all is fine, since everything evaluates to other
+		return nil
+	}
+
+	var errors []error
+	var rules [PluralTypeOther + 1]relation
+	var allSamples []typedSample
+
+	for i, ruleString := range d.ruleStrings() {
+		pluralType := PluralType(i)
+
+		parsedRule, err := tryParsePluralRule(ruleString)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if parsedRule == nil {
+			continue
+		}
+		rule := parsedRule.compile()
+		rules[pluralType] = rule
+
+		samples, err := tryParsePluralRuleSample(parsedRule.Samples, ruleString)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if err := validatePluralRuleSamples(rule, samples, ruleString); err != nil {
+			errors = append(errors, err)
+		}
+
+		for _, sample := range samples {
+			allSamples = append(allSamples, typedSample{pluralType, createCompactOperands(sample), sample})
+		}
+	}
+
+	if len(d.Other) == 0 {
+		errors = append(errors, PluralRulesMissingOtherError{})
+	}
+
+	for _, s := range allSamples {
+		var matchingTypes []PluralType
+		for pluralType, rule := range rules[:PluralTypeOther] {
+			if rule != nil && rule(s.sample) {
+				matchingTypes = append(matchingTypes, PluralType(pluralType))
+			}
+		}
+
+		// a sample for other that matches another rule will never make it to other, so is also an overlap
+		if s.pluralType == PluralTypeOther && len(matchingTypes) > 0 {
+			matchingTypes = append(matchingTypes, PluralTypeOther)
+		}
+		if len(matchingTypes) > 1 {
+			errors = append(errors, PluralRulesOverlapError{s.raw, matchingTypes})
+		}
+
+		other := rules[PluralTypeOther]
+		if len(matchingTypes) == 0 && other != nil && !other(s.sample) {
+			errors = append(errors, PluralRulesUncaughtSampleError{s.raw})
+		}
+	}
+
+	if len(errors) > 0 {
+		return PluralRulesDefinitionError{errors}
+	}
+	return nil
+}
+
+func validatePluralRule(pluralRule relation, sampleString string, ruleString string) error {
 	samples := parsePluralRuleSample(sampleString)
-	var invalidSamples []decimal.Decimal
+	return validatePluralRuleSamples(pluralRule, samples, ruleString)
+}
+
+func validatePluralRuleSamples(pluralRule relation, samples []CompactDecimal, ruleString string) error {
+	var invalidSamples []CompactDecimal
 
 	for _, sample := range samples {
-		result := pluralRule(sample)
+		result := pluralRule(createCompactOperands(sample))
 		if !result {
 			invalidSamples = append(invalidSamples, sample)
 		}
@@ -40,45 +158,62 @@ func validatePluralRule(pluralRule PluralRuleOperation, sampleString string, rul
 	return nil
 }
 
-func parsePluralRuleSample(sample string) []decimal.Decimal {
-	tokens := tokenizePluralRuleSample(sample)
-	var results []decimal.Decimal
-
-	var lowValue decimal.Decimal
-	isRange := false
-	for _, t := range tokens {
-		if t.Kind == tokenIntegerSample || t.Kind == tokenDecimalSample {
+func tryParsePluralRuleSample(sample string, ruleString string) (samples []CompactDecimal, err error) {
+	integers, decimals, err := tryParsePluralRuleSamples(sample, ruleString)
+	return append(integers, decimals...), err
+}
 
-			//since the decimal type represents both just fine, we don't otherwise care about these
-			continue
+func tryParsePluralRuleSamples(sample string, ruleString string) (integers, decimals []CompactDecimal, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = PluralRuleParseError{ruleString, fmt.Sprint(r)}
 		}
+	}()
 
-		var one = decimal.New(1, 0)
+	integers, decimals = parsePluralRuleSamples(sample)
+	return
+}
+
+func parsePluralRuleSample(sample string) []CompactDecimal {
+	integers, decimals := parsePluralRuleSamples(sample)
+	return append(integers, decimals...)
+}
+
+// parsePluralRuleSamples keeps the @integer and @decimal samples separate
+func parsePluralRuleSamples(sample string) (integers, decimals []CompactDecimal) {
+	tokens := tokenizePluralRuleSample(sample)
+	results := &integers
 
+	var lowValue CompactDecimal
+	isRange := false
+	for _, t := range tokens {
 		switch t.Kind {
 		case tokenNumber:
-			n := decimal.RequireFromString(t.Value)
+			n, err := ParseCompactDecimal(t.Value)
+			if err != nil {
+				panic(err)
+			}
 
 			if isRange {
 				// easiest way to get number of decimal digits
-				o := createOperands(n)
-				increment := one.Pow(o.V.Neg())
-				for d := lowValue.Add(increment); d.LessThanOrEqual(n); d = d.Add(increment) {
-					results = append(results, d)
+				o := createOperands(n.Significand)
+				increment := decimal.New(1, -int32(o.V.IntPart()))
+				for d := lowValue.Significand.Add(increment); d.LessThanOrEqual(n.Significand); d = d.Add(increment) {
+					*results = append(*results, CompactDecimal{d, n.Exponent})
 				}
 
 				isRange = false
 			} else {
-				results = append(results, n)
+				*results = append(*results, n)
 				lowValue = n
 			}
 		case tokenRange:
 			isRange = true
 
 		case tokenIntegerSample:
-			continue
+			results = &integers
 		case tokenDecimalSample:
-			continue
+			results = &decimals
 		case tokenComma:
 			continue
 		case tokenTripleDot:
@@ -89,7 +224,7 @@ func parsePluralRuleSample(sample string) []decimal.Decimal {
 		}
 	}
 
-	return results
+	return
 }
 
 func tokenizePluralRuleSample(sample string) []token {
@@ -105,28 +240,22 @@ func tokenizePluralRuleSample(sample string) []token {
 	var tokens []token
 	const noValue = ""
 
+	// for compact exponential notation, like 1.2c6, the scanner gives us the significand, the c, then the exponent
+	// so we hold onto the significand until we find the exponent
+	var compactSignificand string
 	var foundExponentialNotation bool
 ScanLoop:
 	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		// we dont support exponential notation, so we need to strip the prior number
-		// and ignore the next number if we find it
-
 		switch tok {
 		case scanner.Ident:
 			break
-		case scanner.Int:
+		case scanner.Int, scanner.Float:
+			value := s.TokenText()
 			if foundExponentialNotation {
 				foundExponentialNotation = false
-				continue ScanLoop
+				value = fmt.Sprint(compactSignificand, "c", value)
 			}
-			tokens = append(tokens, token{tokenNumber, s.TokenText()})
-			continue ScanLoop
-		case scanner.Float:
-			if foundExponentialNotation {
-				foundExponentialNotation = false
-				continue ScanLoop
-			}
-			tokens = append(tokens, token{tokenNumber, s.TokenText()})
+			tokens = append(tokens, token{tokenNumber, value})
 			continue ScanLoop
 		case '~':
 			tokens = append(tokens, token{tokenRange, noValue})
@@ -134,11 +263,9 @@ ScanLoop:
 		case ',':
 			tokens = append(tokens, token{tokenComma, noValue})
 			continue ScanLoop
-		case 'c':
-			tokens = tokens[:len(tokens)-1]
-			foundExponentialNotation = true
-			continue ScanLoop
-		case 'e':
+		case 'c', 'e':
+			// e is a deprecated synonym for c
+			compactSignificand = tokens[len(tokens)-1].Value
 			tokens = tokens[:len(tokens)-1]
 			foundExponentialNotation = true
 			continue ScanLoop
@@ -1,18 +1,28 @@
 package gettext
 
 import (
+	"cmp"
 	"fmt"
 	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/shopspring/decimal"
+	"golang.org/x/text/language"
 	"golang.org/x/text/unicode/cldr"
 )
 
 type PluralRuleOperation func(decimal.Decimal) bool
 
 type PluralRules struct {
-	zero, one, two, few, many, other PluralRuleOperation
+	// both are indexed by PluralType, where rules that aren't present are nil
+	rules     [PluralTypeOther + 1]*PluralRule
+	relations [PluralTypeOther + 1]relation
+
+	ranges map[pluralRangeKey]PluralType
 }
 
 type PluralType int
@@ -44,66 +54,298 @@ func (t PluralType) String() string {
 	panic(fmt.Sprint("Unknown PluralType ", int(t)))
 }
 
+// Category returns the CLDR name of the plural category, such as "one" or "other"
+func (t PluralType) Category() string {
+	switch t {
+	case PluralTypeZero:
+		return "zero"
+	case PluralTypeOne:
+		return "one"
+	case PluralTypeTwo:
+		return "two"
+	case PluralTypeFew:
+		return "few"
+	case PluralTypeMany:
+		return "many"
+	case PluralTypeOther:
+		return "other"
+	}
+	panic(fmt.Sprint("Unknown PluralType ", int(t)))
+}
+
+func PluralTypeFromCategory(category string) (PluralType, bool) {
+	switch category {
+	case "zero":
+		return PluralTypeZero, true
+	case "one":
+		return PluralTypeOne, true
+	case "two":
+		return PluralTypeTwo, true
+	case "few":
+		return PluralTypeFew, true
+	case "many":
+		return PluralTypeMany, true
+	case "other":
+		return PluralTypeOther, true
+	}
+	return PluralTypeOther, false
+}
+
 type DefaultPluralRulesNotFoundError struct {
 	Locale string
 }
 
 func (p *PluralRules) Evaluate(d decimal.Decimal) PluralType {
-	switch {
-	case p.zero != nil && p.zero(d):
-		return PluralTypeZero
-	case p.one != nil && p.one(d):
-		return PluralTypeOne
-	case p.two != nil && p.two(d):
-		return PluralTypeTwo
-	case p.few != nil && p.few(d):
-		return PluralTypeFew
-	case p.many != nil && p.many(d):
-		return PluralTypeMany
-	case p.other != nil && p.other(d):
-		return PluralTypeOther
-	default:
-		// it is expected that the other rule will always be present and evaluate to true
-		//panic("No rules evaluated to true.")
+	return p.evaluateOperands(createOperands(d))
+}
+
+// EvaluateCompact is for numbers displayed in compact form, such as "1.2 million",
+// since some languages use a different plural type for them than they would for the full number.
+func (p *PluralRules) EvaluateCompact(c CompactDecimal) PluralType {
+	return p.evaluateOperands(createCompactOperands(c))
+}
+
+func (p *PluralRules) EvaluateInt(n int64) PluralType {
+	return p.Evaluate(decimal.NewFromInt(n))
+}
 
-		// going with returning Other, since it's expected for Other to always match if others fail
+// EvaluateFloat evaluates the number as it would be displayed with the given number of fractional digits,
+// since 1.50 and 1.5 can have different plural types. A precision of -1 displays as few digits as necessary.
+// NaN and infinities, which don't have plural rules, evaluate to PluralTypeOther.
+func (p *PluralRules) EvaluateFloat(f float64, precision int) PluralType {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
 		return PluralTypeOther
 	}
+
+	return p.Evaluate(decimal.RequireFromString(strconv.FormatFloat(f, 'f', precision, 64)))
+}
+
+// EvaluateString evaluates an already-formatted number, keeping trailing zeroes, such as "1.50".
+// The number must use . as the decimal separator and not have grouping separators.
+// Compact numbers, such as "1.2c6", are also supported.
+func (p *PluralRules) EvaluateString(s string) (PluralType, error) {
+	c, err := ParseCompactDecimal(strings.TrimSpace(s))
+	if err != nil {
+		return PluralTypeOther, err
+	}
+
+	return p.EvaluateCompact(c), nil
+}
+
+func (p *PluralRules) evaluateOperands(o operands) PluralType {
+	for pluralType, relation := range p.relations {
+		if relation != nil && relation(o) {
+			return PluralType(pluralType)
+		}
+	}
+
+	// it is expected that the other rule will always be present and evaluate to true
+	//panic("No rules evaluated to true.")
+
+	// going with returning Other, since it's expected for Other to always match if others fail
+	return PluralTypeOther
+}
+
+// Categories returns the plural types the rules use, in PluralType order. PluralMapping has the msgstr[n] each one uses.
+// PluralTypeOther is always included, since everything that no other rule matches evaluates to it.
+func (p *PluralRules) Categories() []PluralType {
+	var result []PluralType
+	for i, rule := range p.rules[:PluralTypeOther] {
+		if rule != nil {
+			result = append(result, PluralType(i))
+		}
+	}
+	return append(result, PluralTypeOther)
+}
+
+// Index returns n of the msgstr[n] used for the plural type, or false if the rules don't use the plural type.
+func (p *PluralRules) Index(t PluralType) (int, bool) {
+	return slices.BinarySearch(p.Categories(), t)
+}
+
+// EvaluateIndex returns n of the msgstr[n] to use for the number.
+func (p *PluralRules) EvaluateIndex(d decimal.Decimal) int {
+	// since Evaluate only returns categories the rules use, the index is always found
+	index, _ := p.Index(p.Evaluate(d))
+	return index
+}
+
+// Rule returns the parsed rule for the plural type, which can be inspected, printed, or compiled into a PluralRuleOperation.
+func (p *PluralRules) Rule(t PluralType) (*PluralRule, bool) {
+	rule := p.rules[t]
+	return rule, rule != nil
+}
+
+// Definition converts the rules back into their string form, such as for writing them to a header.
+func (p *PluralRules) Definition() PluralRulesDefinition {
+	var ruleStrings [PluralTypeOther + 1]string
+	for i, rule := range p.rules {
+		if rule != nil {
+			ruleStrings[i] = rule.String()
+		}
+	}
+
+	d := PluralRulesDefinition{
+		Zero:  ruleStrings[PluralTypeZero],
+		One:   ruleStrings[PluralTypeOne],
+		Two:   ruleStrings[PluralTypeTwo],
+		Few:   ruleStrings[PluralTypeFew],
+		Many:  ruleStrings[PluralTypeMany],
+		Other: ruleStrings[PluralTypeOther],
+	}
+	for key, result := range p.ranges {
+		d.Ranges = append(d.Ranges, PluralRange{key.start, key.end, result})
+	}
+	slices.SortFunc(d.Ranges, func(a, b PluralRange) int {
+		return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.End, b.End))
+	})
+
+	return d
+}
+
+// Equal compares the conditions of each rule, ignoring samples, as well as the ranges.
+func (p *PluralRules) Equal(other *PluralRules) bool {
+	for i, rule := range p.rules {
+		if !rule.Equal(other.rules[i]) {
+			return false
+		}
+	}
+
+	return maps.Equal(p.ranges, other.ranges)
+}
+
+// HeaderFields formats the rules as header fields, such as "X-PluralRules-One: n = 1 @integer 1\n",
+// where the prefix is either PluralRulesHeaderPrefix or OrdinalRulesHeaderPrefix.
+func (p *PluralRules) HeaderFields(prefix string) string {
+	var sb strings.Builder
+	for i, rule := range p.rules {
+		if rule == nil {
+			continue
+		}
+
+		// capitalized to match the usual header field casing, though they are parsed case-insensitively
+		category := PluralType(i).Category()
+		fmt.Fprint(&sb, prefix, strings.ToUpper(category[:1]), category[1:], ": ", rule, "\n")
+	}
+	return sb.String()
 }
 
+const (
+	PluralRulesHeaderPrefix  = "X-PluralRules-"
+	OrdinalRulesHeaderPrefix = "X-OrdinalRules-"
+)
+
 func (e DefaultPluralRulesNotFoundError) Error() string {
 	return fmt.Sprint("Plural rules for locale '", e.Locale, "' not found.")
 }
 
 type PluralRulesDefinition struct {
 	Zero, One, Two, Few, Many, Other string
+
+	Ranges []PluralRange
 }
 
 func GetDefaultPluralRulesDefinition(localeCode string) (PluralRulesDefinition, error) {
-	// TODO: en-us should resolve to en
-	if result, ok := defaultPluralRulesDefinitions[localeCode]; ok {
+	if result, ok := lookupDefinition(localeCode, "cardinal", defaultPluralRulesDefinitions); ok {
 		return result, nil
 	}
 	return PluralRulesDefinition{}, DefaultPluralRulesNotFoundError{localeCode}
 }
 
+// localeCandidates lists the locale, then less specific locales, so that en-US and en_US resolve to en,
+// since CLDR only lists locales whose rules differ from their parent.
+func localeCandidates(localeCode string) []string {
+	candidates := []string{localeCode}
+
+	tag, err := language.Parse(strings.ReplaceAll(localeCode, "_", "-"))
+	if err != nil {
+		return candidates
+	}
+
+	for ; tag != language.Und; tag = tag.Parent() {
+		// cldr uses underscores, like pt_PT
+		candidates = append(candidates, localeKey(tag))
+		if base, confidence := tag.Base(); confidence == language.Exact {
+			candidates = append(candidates, base.String())
+		}
+	}
+
+	return candidates
+}
+
+func localeKey(tag language.Tag) string {
+	return strings.ReplaceAll(tag.String(), "-", "_")
+}
+
 func (d *PluralRulesDefinition) Parse() PluralRules {
-	return PluralRules{
-		zero:  parsePluralRule(d.Zero),
-		one:   parsePluralRule(d.One),
-		two:   parsePluralRule(d.Two),
-		few:   parsePluralRule(d.Few),
-		many:  parsePluralRule(d.Many),
-		other: parsePluralRule(d.Other),
+	var rules [PluralTypeOther + 1]*PluralRule
+	for i, ruleString := range d.ruleStrings() {
+		rules[i] = parsePluralRule(ruleString)
 	}
+
+	return createPluralRules(rules, d.Ranges)
+}
+
+// compile is for rules that don't come from the official xml, so returns an error instead of panicing.
+// the rules are not validated against their samples; see Validate for that.
+func (d *PluralRulesDefinition) compile() (PluralRules, error) {
+	var rules [PluralTypeOther + 1]*PluralRule
+	for i, ruleString := range d.ruleStrings() {
+		rule, err := tryParsePluralRule(ruleString)
+		if err != nil {
+			return PluralRules{}, err
+		}
+		rules[i] = rule
+	}
+
+	return createPluralRules(rules, d.Ranges), nil
 }
 
-var defaultPluralRulesDefinitions = func() map[string]PluralRulesDefinition {
-	supplemental := cldrData.Supplemental()
-	// dont need to keep this up-to-date, but counted 182 below
+func createPluralRules(rules [PluralTypeOther + 1]*PluralRule, ranges []PluralRange) PluralRules {
+	result := PluralRules{
+		rules:  rules,
+		ranges: createPluralRanges(ranges),
+	}
+
+	for i, rule := range rules {
+		if rule != nil {
+			result.relations[i] = rule.compile()
+		}
+	}
+
+	return result
+}
+
+// ruleStrings is indexed by PluralType
+func (d *PluralRulesDefinition) ruleStrings() [PluralTypeOther + 1]string {
+	return [...]string{d.Zero, d.One, d.Two, d.Few, d.Many, d.Other}
+}
+
+var defaultPluralRulesDefinitions = loadCardinalRulesDefinitions(cldrData)
+
+func loadCardinalRulesDefinitions(data *cldr.CLDR) map[string]PluralRulesDefinition {
+	result := loadPluralRulesDefinitions(data, "cardinal")
+
+	for locale, ranges := range loadPluralRanges(data) {
+		if d, ok := result[locale]; ok {
+			d.Ranges = ranges
+			result[locale] = d
+		}
+	}
+
+	return result
+}
+
+func loadPluralRulesDefinitions(data *cldr.CLDR, pluralsType string) map[string]PluralRulesDefinition {
+	supplemental := data.Supplemental()
+	// dont need to keep this up-to-date, but counted 182 cardinal locales
 	result := make(map[string]PluralRulesDefinition, 182)
 
 	for _, plural := range supplemental.Plurals {
+		if plural.Type != pluralsType {
+			continue
+		}
+
 		for _, pluralRules := range plural.PluralRules {
 			var pr PluralRulesDefinition
 			for _, pluralRule := range pluralRules.PluralRule {
@@ -132,14 +374,20 @@ var defaultPluralRulesDefinitions = func() map[string]PluralRulesDefinition {
 	}
 
 	return result
-}()
+}
 
 type pluralLoader struct{}
 
-func (ld pluralLoader) Len() int          { return 1 }
-func (ld pluralLoader) Path(_ int) string { return "common/supplemental/plurals.xml" }
-func (ld pluralLoader) Reader(_ int) (io.ReadCloser, error) {
-	reader := strings.NewReader(sourcePluralRules)
+var pluralLoaderFiles = [...]struct{ path, source string }{
+	{"common/supplemental/plurals.xml", sourcePluralRules},
+	{"common/supplemental/ordinals.xml", sourceOrdinalRules},
+	{"common/supplemental/pluralRanges.xml", sourcePluralRanges},
+}
+
+func (ld pluralLoader) Len() int          { return len(pluralLoaderFiles) }
+func (ld pluralLoader) Path(i int) string { return pluralLoaderFiles[i].path }
+func (ld pluralLoader) Reader(i int) (io.ReadCloser, error) {
+	reader := strings.NewReader(pluralLoaderFiles[i].source)
 	readCloser := io.NopCloser(reader)
 	return readCloser, nil
 }
@@ -150,14 +398,20 @@ type operands struct {
 	V, // count of fractional digits, including trailing zeroes
 	W, // count of fractional digits, excluding trailing zeroes
 	F, // fractional digits, including trailing zeroes, expressed as integer
-	T decimal.Decimal // fractional digits, excluding trailing zeroes, expressed as integer
-	// since the language doesnt distinguish compact exponential form, we dont have `c` and `e` here
+	T, // fractional digits, excluding trailing zeroes, expressed as integer
+	C, // compact decimal exponent
+	E decimal.Decimal // a deprecated synonym for c
 }
 
 var one = decimal.New(1, 0)
 
 func createOperands(number decimal.Decimal) operands {
-	n := number.Abs()
+	return createCompactOperands(CompactDecimal{Significand: number})
+}
+
+// the other operands are based on the full number, so 1.2c3 is 1200, with no fractional digits
+func createCompactOperands(number CompactDecimal) operands {
+	n := number.Decimal().Abs()
 	// the C# impl allows negative values for i, f, and t, but not actually sure which is more correct
 	// the language describing the operands seems to indicate always positive, so going with that here
 	i, fracPart := n.QuoRem(one, 0)
@@ -189,6 +443,8 @@ func createOperands(number decimal.Decimal) operands {
 		W: decimal.NewFromInt(int64(w)),
 		F: f,
 		T: t,
+		C: decimal.NewFromInt32(number.Exponent),
+		E: decimal.NewFromInt32(number.Exponent),
 	}
 	return result
 }
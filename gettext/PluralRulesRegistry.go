@@ -0,0 +1,53 @@
+package gettext
+
+import (
+	"maps"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	registeredPluralDataMutex sync.RWMutex
+	registeredPluralData      = pluralData{}
+)
+
+// RegisterPluralRulesDefinition adds or overrides the default plural rules of a locale,
+// such as for constructed or regional locales that CLDR doesn't have rules for.
+// The rules are validated like header rules are; see PluralRulesDefinition.Validate.
+// Registered rules take precedence over both the built-in rules and rules loaded from a CLDR release.
+func RegisterPluralRulesDefinition(tag language.Tag, d PluralRulesDefinition) error {
+	return registerDefinition(tag, "cardinal", d)
+}
+
+// RegisterOrdinalRulesDefinition is like RegisterPluralRulesDefinition, but for ordinal rules.
+func RegisterOrdinalRulesDefinition(tag language.Tag, d PluralRulesDefinition) error {
+	return registerDefinition(tag, "ordinal", d)
+}
+
+func registerDefinition(tag language.Tag, pluralsType string, d PluralRulesDefinition) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	registeredPluralDataMutex.Lock()
+	defer registeredPluralDataMutex.Unlock()
+
+	// copied so that lookups that already have the old map aren't written to while reading it
+	definitions := maps.Clone(registeredPluralData[pluralsType])
+	if definitions == nil {
+		definitions = make(map[string]PluralRulesDefinition)
+	}
+	definitions[localeKey(tag)] = d
+	registeredPluralData[pluralsType] = definitions
+
+	return nil
+}
+
+// ResetRegisteredPluralRules removes all registered plural and ordinal rules, such as for cleaning up after tests.
+func ResetRegisteredPluralRules() {
+	registeredPluralDataMutex.Lock()
+	defer registeredPluralDataMutex.Unlock()
+
+	registeredPluralData = pluralData{}
+}
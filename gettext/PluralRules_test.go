@@ -7,3 +7,50 @@ func TestAllDefaultPluralRules(t *testing.T) {
 		_ = def.Parse() //panics if parsing or sample validation fails
 	}
 }
+
+func TestAllDefaultPluralRulesPassFullValidation(t *testing.T) {
+	for locale, def := range defaultPluralRulesDefinitions {
+		if err := def.Validate(); err != nil {
+			t.Errorf("Plural rules for '%v' failed validation: %v", locale, err)
+		}
+	}
+}
+
+func TestAllDefaultOrdinalRules(t *testing.T) {
+	for locale, def := range defaultOrdinalRulesDefinitions {
+		_ = def.Parse()
+		if err := def.Validate(); err != nil {
+			t.Errorf("Ordinal rules for '%v' failed validation: %v", locale, err)
+		}
+	}
+}
+
+func TestAllDefaultPluralRangesHaveRules(t *testing.T) {
+	for locale := range loadPluralRanges(cldrData) {
+		if _, ok := defaultPluralRulesDefinitions[locale]; !ok {
+			t.Errorf("Plural ranges for '%v' have no corresponding plural rules.", locale)
+		}
+	}
+}
+
+func TestAllDefaultPluralRulesRoundTripThroughDefinition(t *testing.T) {
+	for locale, def := range defaultPluralRulesDefinitions {
+		rules := def.Parse()
+		roundTrippedDefinition := rules.Definition()
+		roundTripped := roundTrippedDefinition.Parse()
+
+		if !rules.Equal(&roundTripped) {
+			t.Errorf("Plural rules for '%v' changed after round tripping: %+v", locale, roundTrippedDefinition)
+		}
+	}
+}
+
+func TestAllDefaultPluralRulesConvertToPluralForms(t *testing.T) {
+	for locale, def := range defaultPluralRulesDefinitions {
+		rules := def.Parse()
+		forms := rules.PluralForms()
+		if err := forms.Compare(&rules); err != nil {
+			t.Errorf("Plural forms for '%v' disagree with the plural rules: %v %v", locale, forms.String(), err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package gettext
+
+import "github.com/shopspring/decimal"
+
+// PluralSamples are example numbers for a plural type, such as for previewing or testing each form of a message.
+type PluralSamples struct {
+	Integers []CompactDecimal
+	Decimals []CompactDecimal
+}
+
+// the most samples found by searching, per plural type and per integer or decimal
+const maxSearchedSamples = 16
+
+// Samples returns example numbers for each plural type of Categories.
+// A rule's own @integer and @decimal samples are used when it has them, as the CLDR rules do.
+// Otherwise, such as for rules from headers without samples, samples are found by searching through numbers.
+func (p *PluralRules) Samples() map[PluralType]PluralSamples {
+	result := make(map[PluralType]PluralSamples, len(p.rules))
+	needsSearch := make(map[PluralType]bool, len(p.rules))
+
+	for i, rule := range p.rules {
+		pluralType := PluralType(i)
+		if rule == nil {
+			// like Categories, other is always used, even when the rules leave it out
+			if pluralType == PluralTypeOther {
+				needsSearch[pluralType] = true
+			}
+			continue
+		}
+
+		integers, decimals, err := tryParsePluralRuleSamples(rule.Samples, rule.String())
+		if err != nil || len(integers)+len(decimals) == 0 {
+			needsSearch[pluralType] = true
+			continue
+		}
+
+		result[pluralType] = PluralSamples{integers, decimals}
+	}
+
+	if len(needsSearch) > 0 {
+		p.searchSamples(result, needsSearch)
+	}
+
+	return result
+}
+
+func GetDefaultPluralSamples(localeCode string) (map[PluralType]PluralSamples, error) {
+	d, err := GetDefaultPluralRulesDefinition(localeCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := d.Parse()
+	return rules.Samples(), nil
+}
+
+func (p *PluralRules) searchSamples(result map[PluralType]PluralSamples, needsSearch map[PluralType]bool) {
+	add := func(n CompactDecimal, isDecimal bool) {
+		pluralType := p.EvaluateCompact(n)
+		if !needsSearch[pluralType] {
+			return
+		}
+
+		samples := result[pluralType]
+		if isDecimal && len(samples.Decimals) < maxSearchedSamples {
+			samples.Decimals = append(samples.Decimals, n)
+		} else if !isDecimal && len(samples.Integers) < maxSearchedSamples {
+			samples.Integers = append(samples.Integers, n)
+		}
+		result[pluralType] = samples
+	}
+
+	for _, n := range searchedIntegers {
+		add(CompactDecimal{Significand: n}, false)
+	}
+	for _, n := range searchedDecimals {
+		add(CompactDecimal{Significand: n}, true)
+	}
+}
+
+// rules care about the last few digits and about large round numbers, so we search through small numbers,
+// then round numbers and those just past them.
+// for decimals, rules care about the number of fractional digits, so we search through one and two digits.
+var searchedIntegers, searchedDecimals = func() (integers, decimals []decimal.Decimal) {
+	for i := int64(0); i <= 200; i++ {
+		integers = append(integers, decimal.NewFromInt(i))
+	}
+	for power := int64(1000); power <= 10000000; power *= 10 {
+		for offset := int64(0); offset <= 25; offset++ {
+			integers = append(integers, decimal.NewFromInt(power+offset))
+		}
+	}
+
+	for i := int64(0); i <= 250; i++ {
+		decimals = append(decimals, decimal.New(i, -1))
+	}
+	for i := int64(0); i <= 250; i++ {
+		decimals = append(decimals, decimal.New(i, -2))
+	}
+	for power := int64(1000); power <= 10000000; power *= 10 {
+		decimals = append(decimals, decimal.New(power*10, -1), decimal.New(power*10+1, -1))
+	}
+
+	return
+}()
@@ -0,0 +1,121 @@
+package gettext_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+const cldrPlurals = `<?xml version="1.0" encoding="UTF-8" ?>
+<!DOCTYPE supplementalData SYSTEM "../../common/dtd/ldmlSupplemental.dtd">
+<supplementalData>
+    <version number="$Revision$"/>
+    <plurals type="cardinal">
+        <pluralRules locales="ja">
+            <pluralRule count="one">i = 1 and v = 0 @integer 1</pluralRule>
+            <pluralRule count="other"> @integer 0, 2~16, 100, 1000, 10000, 100000, 1000000, … @decimal 0.0~1.5, 10.0, 100.0, 1000.0, 10000.0, 100000.0, 1000000.0, …</pluralRule>
+        </pluralRules>
+    </plurals>
+</supplementalData>
+`
+
+func TestLoadCldrPluralDataFromPath(t *testing.T) {
+	defer gettext.ResetCldrPluralData()
+
+	dir := t.TempDir()
+	writeCldrFile(t, dir, "plurals.xml", cldrPlurals)
+
+	if err := gettext.LoadCldrPluralDataFromPath(dir); err != nil {
+		t.Fatal("Error loading CLDR data: ", err)
+	}
+	verifyLoadedJapaneseRules(t)
+
+	gettext.ResetCldrPluralData()
+	d, _ := gettext.GetDefaultPluralRulesDefinition("ja")
+	if len(d.One) > 0 {
+		t.Errorf("Expected built-in rules for ja after resetting, got %+v.", d)
+	}
+}
+
+func TestLoadCldrPluralDataFromZip(t *testing.T) {
+	defer gettext.ResetCldrPluralData()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("common/supplemental/plurals.xml")
+	if err != nil {
+		t.Fatal("Error creating zip: ", err)
+	}
+	f.Write([]byte(cldrPlurals))
+	w.Close()
+
+	if err := gettext.LoadCldrPluralDataFromZip(&buf); err != nil {
+		t.Fatal("Error loading CLDR data: ", err)
+	}
+	verifyLoadedJapaneseRules(t)
+}
+
+func TestLoadCldrPluralData_ReturnsError_WhenRulesFailValidation(t *testing.T) {
+	defer gettext.ResetCldrPluralData()
+
+	dir := t.TempDir()
+	writeCldrFile(t, dir, "plurals.xml", `<?xml version="1.0" encoding="UTF-8" ?>
+<supplementalData>
+    <plurals type="cardinal">
+        <pluralRules locales="ja">
+            <pluralRule count="one">i = 1 and v = 0 @integer 1, 2</pluralRule>
+            <pluralRule count="other"> @integer 0, 3~16</pluralRule>
+        </pluralRules>
+    </plurals>
+</supplementalData>
+`)
+
+	err := gettext.LoadCldrPluralDataFromPath(dir)
+	if _, ok := err.(gettext.CldrPluralDataError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.CldrPluralDataError{}, err, err)
+	}
+
+	d, _ := gettext.GetDefaultPluralRulesDefinition("ja")
+	if len(d.One) > 0 {
+		t.Errorf("Expected built-in rules for ja to still be used, got %+v.", d)
+	}
+}
+
+func TestLoadCldrPluralData_ReturnsError_WhenNoRulesFound(t *testing.T) {
+	err := gettext.LoadCldrPluralDataFromPath(t.TempDir())
+	if _, ok := err.(gettext.CldrPluralDataError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.CldrPluralDataError{}, err, err)
+	}
+}
+
+func verifyLoadedJapaneseRules(t *testing.T) {
+	d, err := gettext.GetDefaultPluralRulesDefinition("ja_JP")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	if d.One != "i = 1 and v = 0 @integer 1" {
+		t.Errorf("Expected the loaded rules for ja, got %+v.", d)
+	}
+
+	// locales not in the loaded data fall back to the built-in data
+	if _, err := gettext.GetDefaultPluralRulesDefinition("ru"); err != nil {
+		t.Error("Error getting built-in plural rules: ", err)
+	}
+	if _, err := gettext.GetDefaultOrdinalRulesDefinition("ja"); err != nil {
+		t.Error("Error getting built-in ordinal rules: ", err)
+	}
+}
+
+func writeCldrFile(t *testing.T, dir string, name string, contents string) {
+	supplemental := filepath.Join(dir, "common", "supplemental")
+	if err := os.MkdirAll(supplemental, 0o755); err != nil {
+		t.Fatal("Error creating directory: ", err)
+	}
+	if err := os.WriteFile(filepath.Join(supplemental, name), []byte(contents), 0o644); err != nil {
+		t.Fatal("Error writing file: ", err)
+	}
+}
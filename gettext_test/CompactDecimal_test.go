@@ -0,0 +1,61 @@
+package gettext_test
+
+import (
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/shopspring/decimal"
+)
+
+func TestEvaluateCompact_French(t *testing.T) {
+	d, err := gettext.GetDefaultPluralRulesDefinition("fr")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+
+	verifyCompact := func(s string, pt gettext.PluralType) {
+		c, err := gettext.ParseCompactDecimal(s)
+		if err != nil {
+			t.Fatal("Error parsing compact decimal: ", err)
+		}
+		if p := rules.EvaluateCompact(c); p != pt {
+			t.Errorf("Expected %v for %v, got %v.", pt, s, p)
+		}
+	}
+
+	verifyCompact("1.2c6", gettext.PluralTypeMany)
+	verifyCompact("1c6", gettext.PluralTypeMany)
+	verifyCompact("1.2e6", gettext.PluralTypeMany)
+	verifyCompact("1.2c3", gettext.PluralTypeOther)
+	verifyCompact("1000000", gettext.PluralTypeMany)
+	verifyCompact("1", gettext.PluralTypeOne)
+
+	// the same number, when not displayed compactly, isn't many
+	if p := rules.Evaluate(decimal.RequireFromString("1200000")); p != gettext.PluralTypeOther {
+		t.Errorf("Expected %v for 1200000, got %v.", gettext.PluralTypeOther, p)
+	}
+}
+
+func TestParseCompactDecimal(t *testing.T) {
+	c, err := gettext.ParseCompactDecimal("1.50c3")
+	if err != nil {
+		t.Fatal("Error parsing compact decimal: ", err)
+	}
+
+	if c.Exponent != 3 {
+		t.Errorf("Expected exponent 3, got %v.", c.Exponent)
+	}
+	if expected := decimal.RequireFromString("1500"); !c.Decimal().Equal(expected) {
+		t.Errorf("Expected %v, got %v.", expected, c.Decimal())
+	}
+	if s := c.String(); s != "1.50c3" {
+		t.Errorf("Expected string 1.50c3, got %v.", s)
+	}
+
+	if _, err := gettext.ParseCompactDecimal("1.2c"); err == nil {
+		t.Error("Expected an error for a missing exponent.")
+	} else if _, ok := err.(gettext.CompactDecimalParseError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.CompactDecimalParseError{}, err, err)
+	}
+}
@@ -0,0 +1,125 @@
+package gettext_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+func TestNewDocument_RoundTripsThroughWriting(t *testing.T) {
+	header := gettext.EntryHeader{
+		TranslatorComments: []string{"checked by someone", ""},
+		ExtractedComments:  []string{"shown on the main screen"},
+		References:         []string{"main.go:12"},
+		Flags:              []string{"fuzzy", "c-format"},
+	}
+	entries := []gettext.Entry{
+		gettext.NewEntry(gettext.EntryKey{Id: "foo"}, header, `a\b"c	d`, nil),
+		gettext.NewEntry(gettext.EntryKey{IsContextual: true, Context: "ctx", Id: "line one\nline two"}, gettext.EntryHeader{}, "first\nsecond\n", nil),
+		gettext.NewEntry(gettext.EntryKey{Id: "%d file", IsPlural: true, PluralId: "%d files"}, gettext.EntryHeader{}, "", []string{"%d fajl", "%d fajla", "%d fajlova"}),
+	}
+
+	doc, err := gettext.NewDocument(language.MustParse("sr-Latn-RS"), entries)
+	if err != nil {
+		t.Fatal("Error creating document: ", err)
+	}
+
+	var sb strings.Builder
+	if _, err := doc.WriteTo(&sb); err != nil {
+		t.Fatal("Error writing document: ", err)
+	}
+
+	parsed, err := gettext.ParseDocumentString(sb.String())
+	if err != nil {
+		t.Fatalf("Error parsing written document: %v\n%v", err, sb.String())
+	}
+
+	if parsed.Header.Tag != language.MustParse("sr-Latn-RS") {
+		t.Errorf("Expected language sr-Latn-RS, got %v.", parsed.Header.Tag)
+	}
+	if len(parsed.Entries) != len(doc.Entries) {
+		t.Fatalf("Expected %v entries, got %v.", len(doc.Entries), len(parsed.Entries))
+	}
+
+	for i, expected := range doc.Entries {
+		actual := parsed.Entries[i]
+		if actual.EntryKey != expected.EntryKey || actual.Value != expected.Value || !slices.Equal(actual.PluralValues, expected.PluralValues) {
+			t.Errorf("Expected entry %+v, got %+v.", expected, actual)
+		}
+	}
+
+	actualHeader := parsed.Entries[1].Header
+	if !slices.Equal(actualHeader.TranslatorComments, header.TranslatorComments) ||
+		!slices.Equal(actualHeader.ExtractedComments, header.ExtractedComments) ||
+		!slices.Equal(actualHeader.References, header.References) ||
+		!slices.Equal(actualHeader.Flags, header.Flags) {
+		t.Errorf("Expected entry header %+v, got %+v.", header, actualHeader)
+	}
+	if !actualHeader.IsFuzzy() {
+		t.Error("Expected entry to be fuzzy.")
+	}
+
+	// written again, the document should be the same
+	var sb2 strings.Builder
+	parsed.WriteTo(&sb2)
+	if sb.String() != sb2.String() {
+		t.Errorf("Expected the same document when writing again.\nExpected:\n%v\nGot:\n%v", sb.String(), sb2.String())
+	}
+}
+
+func TestNewDocument_WritesPluralFormsOfLanguage(t *testing.T) {
+	entries := []gettext.Entry{
+		gettext.NewEntry(gettext.EntryKey{Id: "%d file", IsPlural: true, PluralId: "%d files"}, gettext.EntryHeader{}, "", []string{"%d файл", "%d файла", "%d файлов"}),
+	}
+	doc, err := gettext.NewDocument(language.Russian, entries)
+	if err != nil {
+		t.Fatal("Error creating document: ", err)
+	}
+
+	expected := "Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);\n"
+	if !strings.Contains(doc.Entries[0].Value, expected) {
+		t.Errorf("Expected header to contain %v, got:\n%v", expected, doc.Entries[0].Value)
+	}
+	if err := doc.Header.ComparePluralForms(); err != nil {
+		t.Error("Expected the plural forms to agree with the plural rules: ", err)
+	}
+	if err := doc.ValidatePluralCounts(); err != nil {
+		t.Error("Expected three plural values to be valid: ", err)
+	}
+
+	d, err := gettext.GetDefaultPluralRulesDefinition("ru")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	// plural ranges aren't header fields
+	d.Ranges = nil
+	if rules := d.Parse(); !doc.Header.PluralRules.Equal(&rules) {
+		t.Errorf("Expected the header to have the default rules, got %+v.", doc.Header.PluralRules.Definition())
+	}
+}
+
+func TestWriteTo_KeepsParsedDocumentAsIs(t *testing.T) {
+	documentText := `msgid ""
+msgstr "Language: ja\n"
+
+# comment
+msgctxt "foo"
+msgid "bar"
+msgstr "baz"
+#~ msgid "old"
+#~ msgstr "older"
+`
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var sb strings.Builder
+	doc.WriteTo(&sb)
+	if sb.String() != documentText {
+		t.Errorf("Expected:\n%v\nGot:\n%v", documentText, sb.String())
+	}
+}
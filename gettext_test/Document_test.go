@@ -3,6 +3,7 @@ package gettext_test
 import (
 	"fmt"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/Timiz0r/golocalization/gettext"
@@ -106,6 +107,96 @@ msgstr "Language: az_AZ@latin\n"
 	verifyPlural(decimal.RequireFromString("0.1"), gettext.PluralTypeOther)
 }
 
+func TestReturnsError_WhenValidatingPluralRulesThatFailSamples(t *testing.T) {
+	documentText := `
+msgid ""
+msgstr "Language: en\n"
+"X-PluralRules-One: n = 1 @integer 1, 2\n"
+"X-PluralRules-Other:  @integer 0, 3~16\n"`
+
+	if _, err := gettext.ParseDocumentString(documentText); err != nil {
+		t.Error("Expected no error when not validating plural rules, got: ", err)
+	}
+
+	_, err := gettext.ParseDocumentWithOptions(
+		strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralRules: true})
+	headerErr, ok := err.(gettext.DocumentHeaderParseError)
+	if !ok {
+		t.Fatalf("Expected %T but got %T: %+v", gettext.DocumentHeaderParseError{}, err, err)
+	}
+
+	validationErr, ok := headerErr.UnderlyingError.(gettext.PluralRulesDefinitionError)
+	if !ok {
+		t.Fatalf("Expected %T but got %T: %+v", gettext.PluralRulesDefinitionError{}, headerErr.UnderlyingError, headerErr.UnderlyingError)
+	}
+	if _, ok := validationErr.Errors[0].(gettext.PluralRuleValidationError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.PluralRuleValidationError{}, validationErr.Errors[0], validationErr.Errors[0])
+	}
+}
+
+func TestReturnsError_WhenValidatingPluralRulesThatOverlap(t *testing.T) {
+	documentText := `
+msgid ""
+msgstr "Language: en\n"
+"X-PluralRules-One: n = 1 @integer 1\n"
+"X-PluralRules-Few: n = 1..3 @integer 1~3\n"
+"X-PluralRules-Other:  @integer 0, 4~16\n"`
+
+	_, err := gettext.ParseDocumentWithOptions(
+		strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralRules: true})
+	headerErr, ok := err.(gettext.DocumentHeaderParseError)
+	if !ok {
+		t.Fatalf("Expected %T but got %T: %+v", gettext.DocumentHeaderParseError{}, err, err)
+	}
+
+	validationErr := headerErr.UnderlyingError.(gettext.PluralRulesDefinitionError)
+	overlapErr, ok := validationErr.Errors[0].(gettext.PluralRulesOverlapError)
+	if !ok {
+		t.Fatalf("Expected %T but got %T: %+v", gettext.PluralRulesOverlapError{}, validationErr.Errors[0], validationErr.Errors[0])
+	}
+
+	expectedTypes := []gettext.PluralType{gettext.PluralTypeOne, gettext.PluralTypeFew}
+	if !slices.Equal(expectedTypes, overlapErr.PluralTypes) {
+		t.Errorf("Expected overlapping types %v, got %v.", expectedTypes, overlapErr.PluralTypes)
+	}
+}
+
+func TestReturnsError_WhenValidatingPluralRulesWithoutOther(t *testing.T) {
+	documentText := `
+msgid ""
+msgstr "Language: en\n"
+"X-PluralRules-One: n = 1 @integer 1\n"`
+
+	_, err := gettext.ParseDocumentWithOptions(
+		strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralRules: true})
+	headerErr, ok := err.(gettext.DocumentHeaderParseError)
+	if !ok {
+		t.Fatalf("Expected %T but got %T: %+v", gettext.DocumentHeaderParseError{}, err, err)
+	}
+
+	validationErr := headerErr.UnderlyingError.(gettext.PluralRulesDefinitionError)
+	if _, ok := validationErr.Errors[0].(gettext.PluralRulesMissingOtherError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.PluralRulesMissingOtherError{}, validationErr.Errors[0], validationErr.Errors[0])
+	}
+}
+
+func TestReturnsError_WhenPluralRulesMalformed(t *testing.T) {
+	documentText := `
+msgid ""
+msgstr "Language: en\n"
+"X-PluralRules-One: n = = 1 @integer 1\n"
+"X-PluralRules-Other:  @integer 0, 2~16\n"`
+
+	_, err := gettext.ParseDocumentString(documentText)
+	headerErr, ok := err.(gettext.DocumentHeaderParseError)
+	if !ok {
+		t.Fatalf("Expected %T but got %T: %+v", gettext.DocumentHeaderParseError{}, err, err)
+	}
+	if _, ok := headerErr.UnderlyingError.(gettext.PluralRuleParseError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.PluralRuleParseError{}, headerErr.UnderlyingError, headerErr.UnderlyingError)
+	}
+}
+
 func TestThrows_WhenHeaderEntryIncludesContext(t *testing.T) {
 	documentText := `
 msgctxt ""
@@ -178,3 +269,205 @@ func testLine(t *testing.T, lines []gettext.Line, i int, expectation string, pre
 		t.Errorf("Expected %v. Line: %v", expectation, l)
 	}
 }
+
+func TestValidatePluralCount(t *testing.T) {
+	documentText := genericHeader + `
+msgid "bar"
+msgid_plural "bars"
+msgstr[0] "bazs0"
+msgstr[1] "bazs1"
+msgstr[2] "bazs2"
+
+msgid "foo"
+msgstr "foos"`
+
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	plurals, err := gettext.DefaultPluralMapping(language.German)
+	if err != nil {
+		t.Fatal("Error creating plural mapping: ", err)
+	}
+	err = plurals.ValidatePluralCount(&doc.Entries[1])
+	if _, ok := err.(gettext.EntryPluralCountError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.EntryPluralCountError{}, err, err)
+	}
+
+	plurals, err = gettext.DefaultPluralMapping(language.Romanian)
+	if err != nil {
+		t.Fatal("Error creating plural mapping: ", err)
+	}
+	if err := plurals.ValidatePluralCount(&doc.Entries[1]); err != nil {
+		t.Error("Expected no error for a language with three plural forms: ", err)
+	}
+	if err := plurals.ValidatePluralCount(&doc.Entries[2]); err != nil {
+		t.Error("Expected no error for an entry that isn't plural: ", err)
+	}
+}
+
+func TestValidatePluralCounts_UsesDefaultRulesOfLanguage(t *testing.T) {
+	documentText := `
+msgid ""
+msgstr "Language: ru_RU\n"
+
+msgid "bar"
+msgid_plural "bars"
+msgstr[0] "bazs0"
+msgstr[1] "bazs1"
+
+msgid "foo"
+msgid_plural "foos"
+msgstr[0] "foos0"
+msgstr[1] "foos1"
+msgstr[2] "foos2"`
+
+	_, err := gettext.ParseDocumentWithOptions(strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralCounts: true})
+	countError, ok := err.(gettext.DocumentPluralCountError)
+	if !ok {
+		t.Fatalf("Expected %T but got %T: %+v", gettext.DocumentPluralCountError{}, err, err)
+	}
+	if len(countError.Errors) != 1 || countError.Errors[0].EntryKey.Id != "bar" {
+		t.Errorf("Expected only entry 'bar' to fail, got %+v.", countError.Errors)
+	}
+	// cldr's fourth russian form is only for decimals, so isn't expected
+	if countError.Errors[0].Expected != 3 || countError.Errors[0].Actual != 2 {
+		t.Errorf("Expected 3 plural values but 2 found, got %+v.", countError.Errors[0])
+	}
+
+	// not validated unless asked for
+	if _, err := gettext.ParseDocumentString(documentText); err != nil {
+		t.Error("Error parsing document: ", err)
+	}
+}
+
+func TestValidatePluralCounts_UsesPluralForms(t *testing.T) {
+	documentText := `msgid ""
+msgstr ""
+"Project-Id-Version: files 1.0\n"
+"Language: ru\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && "
+"n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#: src/files.c:42
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"`
+
+	doc, err := gettext.ParseDocumentWithOptions(strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralCounts: true})
+	if err != nil {
+		t.Fatal("Expected a russian document with three plural forms to be valid: ", err)
+	}
+	if err := doc.Header.ComparePluralForms(); err != nil {
+		t.Error("Expected the plural forms to agree with the plural rules: ", err)
+	}
+
+	// a fourth msgstr[n] is one more than the plural forms have
+	documentText = strings.Replace(documentText, "nplurals=3", "nplurals=4", 1)
+	_, err = gettext.ParseDocumentWithOptions(strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralCounts: true})
+	if countError, ok := err.(gettext.DocumentPluralCountError); !ok || countError.Errors[0].Expected != 4 {
+		t.Errorf("Expected %T expecting 4 plural values but got %T: %+v", gettext.DocumentPluralCountError{}, err, err)
+	}
+}
+
+func TestValidatePluralCounts_UsesHeaderRules(t *testing.T) {
+	documentText := `
+msgid ""
+msgstr ""
+"Language: ru\n"
+"X-PluralRules-One: i = 1 and v = 0 @integer 1\n"
+"X-PluralRules-Other: @integer 0, 2~16, 100, 1000, 10000, 100000, 1000000, … @decimal 0.0~1.5, 10.0, 100.0, 1000.0, 10000.0, 100000.0, 1000000.0, …\n"
+
+msgid "bar"
+msgid_plural "bars"
+msgstr[0] "bazs0"
+msgstr[1] "bazs1"`
+
+	_, err := gettext.ParseDocumentWithOptions(strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralCounts: true})
+	if err != nil {
+		t.Error("Expected header rules with two plural types to be used: ", err)
+	}
+}
+
+func TestParsesEscapedQuotesAndBackslashes(t *testing.T) {
+	documentText := genericHeader + `
+msgid "quoted"
+msgstr "say \"hi\" to C:\\"`
+
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	if expected := `say "hi" to C:\`; doc.Entries[1].Value != expected {
+		t.Errorf("Expected '%v' but got '%v'", expected, doc.Entries[1].Value)
+	}
+}
+
+func TestLineValueFromValueEscapesBackslashes(t *testing.T) {
+	if raw := gettext.LineValueFromValue(`C:\dir|x`).Raw; raw != `C:\\dir|x` {
+		t.Errorf("Expected 'C:\\\\dir|x' but got '%v'", raw)
+	}
+}
+
+func TestCompleteLineWritesRawValue(t *testing.T) {
+	line := gettext.CompleteLine(gettext.SimpleKeyword("msgid"), gettext.LineValueFromValue("a\"b\n"), gettext.Comment{IsEmpty: true})
+	if expected := `msgid "a\"b\n"`; line.RawLine != expected {
+		t.Errorf("Expected '%v' but got '%v'", expected, line.RawLine)
+	}
+}
+
+func TestReferencesHaveNoEmptyValues(t *testing.T) {
+	documentText := genericHeader + `
+#: a.go:1
+#: b.go:2
+msgid "foo"
+msgstr "bar"`
+
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	if expected := []string{"a.go:1", "b.go:2"}; !slices.Equal(doc.Entries[1].Header.References, expected) {
+		t.Errorf("Expected %q but got %q", expected, doc.Entries[1].Header.References)
+	}
+}
+
+func TestParsesEmptyComment(t *testing.T) {
+	documentText := genericHeader + `
+#
+msgid "foo"
+msgstr "bar"`
+
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	if len(doc.Entries) != 2 || doc.Entries[1].IsObsolete {
+		t.Errorf("Expected a header and a non-obsolete entry but got %+v", doc.Entries)
+	}
+}
+
+func TestParsesEmptyContext(t *testing.T) {
+	documentText := genericHeader + `
+msgctxt ""
+msgid "foo"
+msgstr "bar"
+
+msgid "foo"
+msgstr "baz"`
+
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	if entry, ok := doc.FindEntry(gettext.EntryKey{IsContextual: true, Id: "foo"}); !ok || entry.Value != "bar" {
+		t.Errorf("Expected the entry with an empty msgctxt but got %+v", entry)
+	}
+}
@@ -0,0 +1,93 @@
+package gettext_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+const updateDocumentText = `msgid ""
+msgstr "Language: ru\n"
+
+# keep   this spacing
+#: main.go:1
+msgid "untouched"
+msgstr "нетронутый"
+
+#, fuzzy
+#| msgid "Old"
+msgid "Save"
+msgstr "Сохр"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+`
+
+func TestUpdateEntry(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(updateDocumentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	value := "Сохранить"
+	header := gettext.EntryHeader{TranslatorComments: []string{"reviewed"}}
+	changed, err := doc.UpdateEntry(gettext.EntryKey{Id: "Save"}, gettext.EntryUpdate{Value: &value, Header: &header})
+	if err != nil || !changed {
+		t.Fatalf("Expected the entry to change, but got: %v %v", changed, err)
+	}
+
+	changed, err = doc.UpdateEntry(gettext.EntryKey{Id: "%d file"}, gettext.EntryUpdate{PluralValues: []string{"%d файл", "%d файла", "%d файлов"}})
+	if err != nil || changed {
+		t.Errorf("Expected the entry to be unchanged, but got: %v %v", changed, err)
+	}
+
+	expected := strings.Replace(updateDocumentText, `#, fuzzy
+#| msgid "Old"
+msgid "Save"
+msgstr "Сохр"`, `# reviewed
+#| msgid "Old"
+msgid "Save"
+msgstr "Сохранить"`, 1)
+
+	var sb strings.Builder
+	if _, err := doc.WriteTo(&sb); err != nil {
+		t.Fatal("Error writing document: ", err)
+	}
+	if sb.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, sb.String())
+	}
+
+	entry, ok := doc.FindEntry(gettext.EntryKey{Id: "Save"})
+	if !ok || entry.Value != value || entry.Header.IsFuzzy() {
+		t.Errorf("Expected to find the updated entry, but got: %+v", entry)
+	}
+}
+
+func TestUpdateEntry_ReturnsError_WhenNotFound(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(updateDocumentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	value := "значение"
+	_, err = doc.UpdateEntry(gettext.EntryKey{IsContextual: true, Context: "ctx", Id: "Save"}, gettext.EntryUpdate{Value: &value})
+	if _, ok := err.(gettext.EntryUpdateError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.EntryUpdateError{}, err, err)
+	}
+}
+
+func TestUpdateEntry_ReturnsError_WhenPluralCountDiffers(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(updateDocumentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	_, err = doc.UpdateEntry(gettext.EntryKey{Id: "%d file"}, gettext.EntryUpdate{PluralValues: []string{"%d файл"}})
+	if _, ok := err.(gettext.EntryPluralCountError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.EntryPluralCountError{}, err, err)
+	}
+}
@@ -0,0 +1,74 @@
+package gettext_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/shopspring/decimal"
+)
+
+func TestDefaultOrdinalRules_English(t *testing.T) {
+	d, err := gettext.GetDefaultOrdinalRulesDefinition("en")
+	if err != nil {
+		t.Fatal("Error getting ordinal rules: ", err)
+	}
+	rules := d.Parse()
+
+	verifyOrdinal := func(n int64, pt gettext.PluralType) {
+		if p := rules.Evaluate(decimal.NewFromInt(n)); p != pt {
+			t.Errorf("Expected %v for %v, got %v.", pt, n, p)
+		}
+	}
+
+	verifyOrdinal(1, gettext.PluralTypeOne)
+	verifyOrdinal(2, gettext.PluralTypeTwo)
+	verifyOrdinal(3, gettext.PluralTypeFew)
+	verifyOrdinal(4, gettext.PluralTypeOther)
+	verifyOrdinal(11, gettext.PluralTypeOther)
+	verifyOrdinal(12, gettext.PluralTypeOther)
+	verifyOrdinal(13, gettext.PluralTypeOther)
+	verifyOrdinal(21, gettext.PluralTypeOne)
+	verifyOrdinal(102, gettext.PluralTypeTwo)
+}
+
+func TestDefaultOrdinalRules_WhenNotFound(t *testing.T) {
+	_, err := gettext.GetDefaultOrdinalRulesDefinition("not-a-locale")
+
+	if _, ok := err.(gettext.DefaultOrdinalRulesNotFoundError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.DefaultOrdinalRulesNotFoundError{}, err, err)
+	}
+}
+
+func TestReturnsRightHeader_WhenOrdinalRulesPresent(t *testing.T) {
+	documentText := `
+msgid ""
+msgstr "Language: sv\n"
+"X-OrdinalRules-One: n % 10 = 1,2 and n % 100 != 11,12 @integer 1, 2, 21, 22, 101, 1001, …\n"
+"X-OrdinalRules-Other:  @integer 0, 3~17, 100, 1000, 10000, 100000, 1000000, …\n"`
+
+	doc, err := gettext.ParseDocumentWithOptions(
+		strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralRules: true})
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	header := doc.Header
+
+	verifyOrdinal := func(n int64, pt gettext.PluralType) {
+		if p := header.OrdinalRules.Evaluate(decimal.NewFromInt(n)); p != pt {
+			t.Errorf("Expected %v for %v, got %v.", pt, n, p)
+		}
+	}
+
+	verifyOrdinal(1, gettext.PluralTypeOne)
+	verifyOrdinal(2, gettext.PluralTypeOne)
+	verifyOrdinal(3, gettext.PluralTypeOther)
+	verifyOrdinal(12, gettext.PluralTypeOther)
+	verifyOrdinal(22, gettext.PluralTypeOne)
+
+	// the cardinal rules are still independent of the ordinal rules
+	if p := header.PluralRules.Evaluate(decimal.NewFromInt(2)); p != gettext.PluralTypeOther {
+		t.Errorf("Expected %v because no plural rules results in %v, got %v.",
+			gettext.PluralTypeOther, gettext.PluralTypeOther, p)
+	}
+}
@@ -0,0 +1,119 @@
+package gettext_test
+
+import (
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+const russianPluralForms = "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"
+
+func TestParsePluralForms(t *testing.T) {
+	forms, err := gettext.ParsePluralForms(russianPluralForms)
+	if err != nil {
+		t.Fatal("Error parsing plural forms: ", err)
+	}
+
+	if forms.NPlurals != 3 {
+		t.Errorf("Expected nplurals of 3, got %v.", forms.NPlurals)
+	}
+	for n, expected := range map[uint64]int{0: 2, 1: 0, 2: 1, 5: 2, 11: 2, 12: 2, 21: 0, 22: 1, 111: 2} {
+		if index := forms.Evaluate(n); index != expected {
+			t.Errorf("Expected %v for %v, got %v.", expected, n, index)
+		}
+	}
+
+	if s := forms.String(); s != russianPluralForms {
+		t.Errorf("Expected %v, got %v.", russianPluralForms, s)
+	}
+}
+
+func TestParsePluralForms_ReturnsError_WhenMalformed(t *testing.T) {
+	for _, s := range []string{
+		"plural=(n != 1);",
+		"nplurals=2; plural=(n != 1;",
+		"nplurals=2; plural=n ? 1;",
+		"nplurals=2; plural=x != 1;",
+		"nplurals=0; plural=0;",
+	} {
+		_, err := gettext.ParsePluralForms(s)
+		if _, ok := err.(gettext.PluralFormsParseError); !ok {
+			t.Errorf("Expected %T for %v but got %T: %+v", gettext.PluralFormsParseError{}, s, err, err)
+		}
+	}
+}
+
+func TestComparePluralForms(t *testing.T) {
+	d, err := gettext.GetDefaultPluralRulesDefinition("de")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+
+	forms, _ := gettext.ParsePluralForms("nplurals=2; plural=(n != 1);")
+	if err := forms.Compare(&rules); err != nil {
+		t.Error("Expected equivalent plural forms: ", err)
+	}
+
+	// the expression agrees, but there's a msgstr[n] that no number uses
+	forms, _ = gettext.ParsePluralForms("nplurals=3; plural=(n != 1);")
+	err = forms.Compare(&rules)
+	if mismatchError, ok := err.(gettext.PluralFormsMismatchError); !ok || mismatchError.ExpectedNPlurals != 2 || len(mismatchError.Mismatches) > 0 {
+		t.Errorf("Expected only an nplurals mismatch but got %T: %+v", err, err)
+	}
+
+	// the rule for french, which treats 0 as singular
+	forms, _ = gettext.ParsePluralForms("nplurals=2; plural=(n > 1);")
+	err = forms.Compare(&rules)
+	mismatchError, ok := err.(gettext.PluralFormsMismatchError)
+	if !ok {
+		t.Fatalf("Expected %T but got %T: %+v", gettext.PluralFormsMismatchError{}, err, err)
+	}
+	expected := gettext.PluralFormsMismatch{N: 0, FormsIndex: 0, RulesIndex: 1, RulesType: gettext.PluralTypeOther}
+	if len(mismatchError.Mismatches) != 1 || mismatchError.Mismatches[0] != expected {
+		t.Errorf("Expected only mismatch %+v, got %+v.", expected, mismatchError.Mismatches)
+	}
+}
+
+func TestComparePluralForms_FromHeader(t *testing.T) {
+	// cldr's russian rules have a fourth form for decimals, which plural forms don't need
+	documentText := `
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: ` + russianPluralForms + `\n"
+`
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	if err := doc.Header.ComparePluralForms(); err != nil {
+		t.Error("Expected equivalent plural forms: ", err)
+	}
+}
+
+func TestComparePluralForms_ReturnsError_WhenHeaderMalformed(t *testing.T) {
+	for _, pluralForms := range []string{
+		// what xgettext writes for the translator to fill in
+		"nplurals=INTEGER; plural=EXPRESSION;",
+		"nplurals=3; plural=(n%10==1;",
+	} {
+		documentText := `
+msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: ` + pluralForms + `\n"
+`
+		doc, err := gettext.ParseDocumentString(documentText)
+		if err != nil {
+			t.Errorf("Expected documents with malformed plural forms to still parse: %v", err)
+			continue
+		}
+
+		err = doc.Header.ComparePluralForms()
+		if _, ok := err.(gettext.PluralFormsParseError); !ok {
+			t.Errorf("Expected %T but got %T: %+v", gettext.PluralFormsParseError{}, err, err)
+		}
+	}
+}
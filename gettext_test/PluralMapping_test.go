@@ -0,0 +1,103 @@
+package gettext_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+func TestPluralMapping_UsesPluralForms(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: ` + russianPluralForms + `\n"`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	plurals, err := doc.Header.PluralMapping()
+	if err != nil {
+		t.Fatal("Error creating plural mapping: ", err)
+	}
+
+	if plurals.NPlurals != 3 {
+		t.Errorf("Expected nplurals of 3, got %v.", plurals.NPlurals)
+	}
+	for pluralType, expected := range map[gettext.PluralType]int{
+		gettext.PluralTypeOne:   0,
+		gettext.PluralTypeFew:   1,
+		gettext.PluralTypeMany:  2,
+		gettext.PluralTypeOther: 2,
+	} {
+		if index, ok := plurals.Index(pluralType); !ok || index != expected {
+			t.Errorf("Expected msgstr[%v] for %v, got %v.", expected, pluralType, index)
+		}
+	}
+	if _, ok := plurals.Index(gettext.PluralTypeZero); ok {
+		t.Errorf("Expected no msgstr[n] for %v, since ru doesn't use it.", gettext.PluralTypeZero)
+	}
+
+	values := plurals.PluralValues(func(pluralType gettext.PluralType) string { return pluralType.Category() })
+	if expected := []string{"one", "few", "many"}; !slices.Equal(values, expected) {
+		t.Errorf("Expected plural values %v, got %v.", expected, values)
+	}
+}
+
+func TestPluralMapping_FollowsPluralFormsOrder(t *testing.T) {
+	d, err := gettext.GetDefaultPluralRulesDefinition("en")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+	// unusual, but valid, with the plural form first
+	forms, err := gettext.ParsePluralForms("nplurals=2; plural=(n == 1);")
+	if err != nil {
+		t.Fatal("Error parsing plural forms: ", err)
+	}
+	plurals := gettext.NewPluralMapping(&rules, &forms)
+
+	pluralValues := []string{"files", "file"}
+	if value := plurals.Value(pluralValues, gettext.PluralTypeOne); value != "file" {
+		t.Errorf("Expected file for one, got %v.", value)
+	}
+	if value := plurals.Value(pluralValues, gettext.PluralTypeOther); value != "files" {
+		t.Errorf("Expected files for other, got %v.", value)
+	}
+	if pluralType := plurals.PluralType(0); pluralType != gettext.PluralTypeOther {
+		t.Errorf("Expected msgstr[0] to be for other, got %v.", pluralType)
+	}
+}
+
+func TestPluralMapping_OnlyCountsFormsIntegersReach_WithoutPluralForms(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	plurals, err := doc.Header.PluralMapping()
+	if err != nil {
+		t.Fatal("Error creating plural mapping: ", err)
+	}
+
+	if plurals.NPlurals != 3 {
+		t.Errorf("Expected nplurals of 3, got %v.", plurals.NPlurals)
+	}
+	if index, _ := plurals.Index(gettext.PluralTypeOther); index != 2 {
+		t.Errorf("Expected other to share msgstr[2] with many, got msgstr[%v].", index)
+	}
+}
+
+func TestPluralMapping_ReturnsError_WhenPluralFormsMalformed(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=INTEGER; plural=EXPRESSION;\n"`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	_, err = doc.Header.PluralMapping()
+	if _, ok := err.(gettext.PluralFormsParseError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.PluralFormsParseError{}, err, err)
+	}
+}
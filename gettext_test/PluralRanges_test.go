@@ -0,0 +1,60 @@
+package gettext_test
+
+import (
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/shopspring/decimal"
+)
+
+func TestEvaluateRange_Russian(t *testing.T) {
+	d, err := gettext.GetDefaultPluralRulesDefinition("ru")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+
+	verifyRange := func(start, end string, pt gettext.PluralType) {
+		if p := rules.EvaluateRange(decimal.RequireFromString(start), decimal.RequireFromString(end)); p != pt {
+			t.Errorf("Expected %v for %v–%v, got %v.", pt, start, end, p)
+		}
+	}
+
+	verifyRange("1", "2", gettext.PluralTypeFew)
+	verifyRange("1", "5", gettext.PluralTypeMany)
+	verifyRange("2", "21", gettext.PluralTypeOne)
+	verifyRange("0", "1.5", gettext.PluralTypeOther)
+}
+
+func TestEvaluateRange_English(t *testing.T) {
+	d, err := gettext.GetDefaultPluralRulesDefinition("en")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+
+	if p := rules.EvaluateRange(decimal.NewFromInt(1), decimal.NewFromInt(3)); p != gettext.PluralTypeOther {
+		t.Errorf("Expected %v for 1–3, got %v.", gettext.PluralTypeOther, p)
+	}
+}
+
+func TestEvaluateRange_ReturnsOther_WhenNoRanges(t *testing.T) {
+	d := gettext.PluralRulesDefinition{
+		One:   "n = 1 @integer 1",
+		Other: " @integer 0, 2~16",
+	}
+	rules := d.Parse()
+
+	if p := rules.EvaluateRange(decimal.NewFromInt(0), decimal.NewFromInt(1)); p != gettext.PluralTypeOther {
+		t.Errorf("Expected %v because no ranges results in %v, got %v.",
+			gettext.PluralTypeOther, gettext.PluralTypeOther, p)
+	}
+}
+
+func TestPluralTypeCategoryRoundTrips(t *testing.T) {
+	for pt := gettext.PluralTypeZero; pt <= gettext.PluralTypeOther; pt++ {
+		if parsed, ok := gettext.PluralTypeFromCategory(pt.Category()); !ok || parsed != pt {
+			t.Errorf("Expected %v to round trip through category %v, got %v.", pt, pt.Category(), parsed)
+		}
+	}
+}
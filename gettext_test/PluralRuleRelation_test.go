@@ -0,0 +1,40 @@
+package gettext_test
+
+import (
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/shopspring/decimal"
+)
+
+func TestNotEqualsNegatesWholeList(t *testing.T) {
+	definition := gettext.PluralRulesDefinition{One: "n != 2,5..7"}
+	rules := definition.Parse()
+
+	cases := map[string]gettext.PluralType{
+		"1":   gettext.PluralTypeOne,
+		"2":   gettext.PluralTypeOther,
+		"5":   gettext.PluralTypeOther,
+		"6":   gettext.PluralTypeOther,
+		"7":   gettext.PluralTypeOther,
+		"8":   gettext.PluralTypeOne,
+		"5.5": gettext.PluralTypeOne,
+	}
+	for number, expected := range cases {
+		if actual := rules.Evaluate(decimal.RequireFromString(number)); actual != expected {
+			t.Errorf("Expected %v for %v but got %v", expected, number, actual)
+		}
+	}
+}
+
+func TestRangesOnlyMatchIntegers(t *testing.T) {
+	definition := gettext.PluralRulesDefinition{One: "n = 0..1"}
+	rules := definition.Parse()
+
+	if actual := rules.Evaluate(decimal.RequireFromString("0.5")); actual != gettext.PluralTypeOther {
+		t.Errorf("Expected %v for 0.5 but got %v", gettext.PluralTypeOther, actual)
+	}
+	if actual := rules.Evaluate(decimal.RequireFromString("1")); actual != gettext.PluralTypeOne {
+		t.Errorf("Expected %v for 1 but got %v", gettext.PluralTypeOne, actual)
+	}
+}
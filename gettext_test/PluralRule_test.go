@@ -0,0 +1,84 @@
+package gettext_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/shopspring/decimal"
+)
+
+func TestPluralRuleAst(t *testing.T) {
+	d := gettext.PluralRulesDefinition{
+		One:   "n % 10 = 1 and n % 100 != 11..19,71 or v = 2 @integer 1, 21 @decimal 0.10",
+		Other: " @integer 0, 2~16",
+	}
+	rules := d.Parse()
+
+	rule, ok := rules.Rule(gettext.PluralTypeOne)
+	if !ok {
+		t.Fatal("Expected a rule for ", gettext.PluralTypeOne)
+	}
+	if _, ok := rules.Rule(gettext.PluralTypeTwo); ok {
+		t.Error("Expected no rule for ", gettext.PluralTypeTwo)
+	}
+
+	if conditionCount := len(rule.Conditions); conditionCount != 2 {
+		t.Fatalf("Expected 2 conditions, got %v.", conditionCount)
+	}
+
+	relation := rule.Conditions[0].Relations[1]
+	if relation.Operand != gettext.PluralOperandN {
+		t.Errorf("Expected operand %v, got %v.", gettext.PluralOperandN, relation.Operand)
+	}
+	if !relation.Modulus.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected modulus 100, got %v.", relation.Modulus)
+	}
+	if !relation.IsNegated {
+		t.Error("Expected a negated relation.")
+	}
+	if rangeCount := len(relation.Ranges); rangeCount != 2 {
+		t.Fatalf("Expected 2 ranges, got %v.", rangeCount)
+	}
+	if r := relation.Ranges[0]; !r.Low.Equal(decimal.NewFromInt(11)) || !r.High.Equal(decimal.NewFromInt(19)) {
+		t.Errorf("Expected range 11..19, got %v.", r)
+	}
+
+	if s := rule.String(); s != d.One {
+		t.Errorf("Expected rule to print as '%v', got '%v'.", d.One, s)
+	}
+	other, _ := rules.Rule(gettext.PluralTypeOther)
+	if s := other.String(); s != d.Other {
+		t.Errorf("Expected rule to print as '%v', got '%v'.", d.Other, s)
+	}
+
+	operation := rule.Operation()
+	if !operation(decimal.NewFromInt(21)) || operation(decimal.NewFromInt(11)) {
+		t.Error("Expected the rule's operation to match 21 but not 11.")
+	}
+}
+
+func TestPluralRulesRoundTripThroughHeader(t *testing.T) {
+	d, err := gettext.GetDefaultPluralRulesDefinition("ru")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+
+	headerValue := "Language: ru\n" + rules.HeaderFields(gettext.PluralRulesHeaderPrefix)
+	documentText := "msgid \"\"\nmsgstr \"" + gettext.LineValueFromValue(headerValue).Raw + "\""
+
+	doc, err := gettext.ParseDocumentWithOptions(
+		strings.NewReader(documentText), gettext.DocumentOptions{ValidatePluralRules: true})
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	// ranges aren't part of the header
+	headerDefinition := doc.Header.PluralRules.Definition()
+	headerDefinition.Ranges = d.Ranges
+	headerRules := headerDefinition.Parse()
+	if !rules.Equal(&headerRules) {
+		t.Errorf("Expected header plural rules to equal the original rules. Header: %v", headerValue)
+	}
+}
@@ -0,0 +1,68 @@
+package gettext_test
+
+import (
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+func TestRegisterPluralRulesDefinition(t *testing.T) {
+	t.Cleanup(gettext.ResetRegisteredPluralRules)
+
+	// klingon, which cldr doesn't have plural rules for
+	tag := language.MustParse("tlh")
+	if _, err := gettext.GetDefaultPluralRulesDefinition("tlh"); err == nil {
+		t.Fatal("Expected tlh to not have built-in rules.")
+	}
+
+	d := gettext.PluralRulesDefinition{
+		One:   "i = 1 and v = 0 @integer 1",
+		Other: " @integer 0, 2~16, 100, 1000, 10000, 100000, 1000000, … @decimal 0.0~1.5, 10.0, 100.0, 1000.0, 10000.0, 100000.0, 1000000.0, …",
+	}
+	if err := gettext.RegisterPluralRulesDefinition(tag, d); err != nil {
+		t.Fatal("Error registering plural rules: ", err)
+	}
+
+	for _, locale := range []string{"tlh", "tlh_US"} {
+		registered, err := gettext.GetDefaultPluralRulesDefinition(locale)
+		if err != nil {
+			t.Errorf("Error getting registered plural rules for %v: %v", locale, err)
+		} else if registered.One != d.One {
+			t.Errorf("Expected registered rules for %v, got %+v.", locale, registered)
+		}
+	}
+
+	// registering again overrides
+	d.One = "i = 1 @integer 1 @decimal 1.0"
+	d.Other = " @integer 0, 2~16 @decimal 0.0~0.9, 2.0"
+	if err := gettext.RegisterPluralRulesDefinition(tag, d); err != nil {
+		t.Fatal("Error registering plural rules: ", err)
+	}
+	if registered, _ := gettext.GetDefaultPluralRulesDefinition("tlh"); registered.One != d.One {
+		t.Errorf("Expected overridden rules, got %+v.", registered)
+	}
+
+	// ordinal rules are registered separately
+	if _, err := gettext.GetDefaultOrdinalRulesDefinition("tlh"); err == nil {
+		t.Error("Expected no ordinal rules for tlh.")
+	}
+}
+
+func TestRegisterPluralRulesDefinition_ReturnsError_WhenSamplesFail(t *testing.T) {
+	t.Cleanup(gettext.ResetRegisteredPluralRules)
+
+	tag := language.MustParse("tlh-x-invalid")
+	d := gettext.PluralRulesDefinition{
+		One:   "i = 1 and v = 0 @integer 1, 2",
+		Other: " @integer 0, 3~16",
+	}
+
+	err := gettext.RegisterOrdinalRulesDefinition(tag, d)
+	if _, ok := err.(gettext.PluralRulesDefinitionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.PluralRulesDefinitionError{}, err, err)
+	}
+	if _, err := gettext.GetDefaultOrdinalRulesDefinition("tlh-x-invalid"); err == nil {
+		t.Error("Expected invalid rules to not be registered.")
+	}
+}
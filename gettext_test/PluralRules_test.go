@@ -0,0 +1,140 @@
+package gettext_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/shopspring/decimal"
+)
+
+func TestEvaluateHelpers_UseDisplayedFractionDigits(t *testing.T) {
+	// en is one only when there are no visible fractional digits
+	d, err := gettext.GetDefaultPluralRulesDefinition("en")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+
+	if p := rules.EvaluateInt(1); p != gettext.PluralTypeOne {
+		t.Errorf("Expected %v for 1, got %v.", gettext.PluralTypeOne, p)
+	}
+	if p := rules.EvaluateFloat(1, -1); p != gettext.PluralTypeOne {
+		t.Errorf("Expected %v for 1 with minimal precision, got %v.", gettext.PluralTypeOne, p)
+	}
+	if p := rules.EvaluateFloat(1, 1); p != gettext.PluralTypeOther {
+		t.Errorf("Expected %v for 1.0, got %v.", gettext.PluralTypeOther, p)
+	}
+	if p := rules.EvaluateFloat(math.NaN(), -1); p != gettext.PluralTypeOther {
+		t.Errorf("Expected %v for NaN, got %v.", gettext.PluralTypeOther, p)
+	}
+
+	verifyString := func(s string, pt gettext.PluralType) {
+		p, err := rules.EvaluateString(s)
+		if err != nil {
+			t.Fatal("Error evaluating string: ", err)
+		}
+		if p != pt {
+			t.Errorf("Expected %v for %v, got %v.", pt, s, p)
+		}
+	}
+	verifyString("1", gettext.PluralTypeOne)
+	verifyString("1.0", gettext.PluralTypeOther)
+}
+
+func TestEvaluateHelpers_DistinguishTrailingZeroes(t *testing.T) {
+	// lv cares about the count of fractional digits (v) and the fractional digits themselves (f)
+	d, err := gettext.GetDefaultPluralRulesDefinition("lv")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+
+	if p := rules.EvaluateFloat(1.1, 1); p != gettext.PluralTypeOne {
+		t.Errorf("Expected %v for 1.1, got %v.", gettext.PluralTypeOne, p)
+	}
+	if p := rules.EvaluateFloat(1.1, 2); p != gettext.PluralTypeOther {
+		t.Errorf("Expected %v for 1.10, got %v.", gettext.PluralTypeOther, p)
+	}
+
+	if p, _ := rules.EvaluateString("1.10"); p != gettext.PluralTypeOther {
+		t.Errorf("Expected %v for 1.10, got %v.", gettext.PluralTypeOther, p)
+	}
+	if p, _ := rules.EvaluateString("0.11"); p != gettext.PluralTypeZero {
+		t.Errorf("Expected %v for 0.11, got %v.", gettext.PluralTypeZero, p)
+	}
+	if _, err := rules.EvaluateString("1,10"); err == nil {
+		t.Error("Expected an error for a number with a comma.")
+	}
+}
+
+func TestCategories_MapToPluralIndexes(t *testing.T) {
+	d, err := gettext.GetDefaultPluralRulesDefinition("ru")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	rules := d.Parse()
+
+	expected := []gettext.PluralType{gettext.PluralTypeOne, gettext.PluralTypeFew, gettext.PluralTypeMany, gettext.PluralTypeOther}
+	if categories := rules.Categories(); !slices.Equal(expected, categories) {
+		t.Errorf("Expected categories %v, got %v.", expected, categories)
+	}
+
+	if index, ok := rules.Index(gettext.PluralTypeMany); !ok || index != 2 {
+		t.Errorf("Expected index 2 for %v, got %v.", gettext.PluralTypeMany, index)
+	}
+	if _, ok := rules.Index(gettext.PluralTypeTwo); ok {
+		t.Errorf("Expected no index for %v, since ru doesn't use it.", gettext.PluralTypeTwo)
+	}
+
+	for n, expectedIndex := range map[int64]int{1: 0, 22: 1, 5: 2} {
+		if index := rules.EvaluateIndex(decimal.NewFromInt(n)); index != expectedIndex {
+			t.Errorf("Expected index %v for %v, got %v.", expectedIndex, n, index)
+		}
+	}
+	if index := rules.EvaluateIndex(decimal.RequireFromString("1.5")); index != 3 {
+		t.Errorf("Expected index 3 for 1.5, got %v.", index)
+	}
+}
+
+func TestCategories_AlwaysIncludeOther(t *testing.T) {
+	var rules gettext.PluralRules
+	expected := []gettext.PluralType{gettext.PluralTypeOther}
+	if categories := rules.Categories(); !slices.Equal(expected, categories) {
+		t.Errorf("Expected categories %v, got %v.", expected, categories)
+	}
+}
+
+func TestGetDefaultPluralRulesDefinition_FallsBackToLessSpecificLocale(t *testing.T) {
+	en, err := gettext.GetDefaultPluralRulesDefinition("en")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+
+	for _, locale := range []string{"en_US", "en-US", "en-Latn-GB"} {
+		d, err := gettext.GetDefaultPluralRulesDefinition(locale)
+		if err != nil {
+			t.Errorf("Error getting plural rules for %v: %v", locale, err)
+			continue
+		}
+		if d.One != en.One || d.Other != en.Other {
+			t.Errorf("Expected rules of %v to be those of en, got %+v.", locale, d)
+		}
+	}
+
+	// pt_PT has its own rules, which differ from pt
+	pt, _ := gettext.GetDefaultPluralRulesDefinition("pt")
+	ptPT, err := gettext.GetDefaultPluralRulesDefinition("pt-PT")
+	if err != nil {
+		t.Fatal("Error getting plural rules: ", err)
+	}
+	if pt.One == ptPT.One {
+		t.Errorf("Expected pt-PT to use its own rules instead of those of pt.")
+	}
+
+	_, err = gettext.GetDefaultPluralRulesDefinition("not a locale")
+	if _, ok := err.(gettext.DefaultPluralRulesNotFoundError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gettext.DefaultPluralRulesNotFoundError{}, err, err)
+	}
+}
@@ -0,0 +1,88 @@
+package gettext_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+func TestSamples_UsesCldrSamples(t *testing.T) {
+	samples, err := gettext.GetDefaultPluralSamples("ru")
+	if err != nil {
+		t.Fatal("Error getting samples: ", err)
+	}
+
+	verifySamples := func(pt gettext.PluralType, expectedIntegers, expectedDecimals []string) {
+		s := samples[pt]
+		if integers := sampleStrings(s.Integers); !slices.Equal(expectedIntegers, integers[:min(len(integers), len(expectedIntegers))]) {
+			t.Errorf("Expected integers of %v to start with %v, got %v.", pt, expectedIntegers, integers)
+		}
+		if decimals := sampleStrings(s.Decimals); !slices.Equal(expectedDecimals, decimals[:min(len(decimals), len(expectedDecimals))]) {
+			t.Errorf("Expected decimals of %v to start with %v, got %v.", pt, expectedDecimals, decimals)
+		}
+	}
+
+	verifySamples(gettext.PluralTypeOne, []string{"1", "21", "31"}, nil)
+	verifySamples(gettext.PluralTypeFew, []string{"2", "3", "4", "22"}, nil)
+	verifySamples(gettext.PluralTypeMany, []string{"0", "5", "6"}, nil)
+	verifySamples(gettext.PluralTypeOther, nil, []string{"0.0", "0.1", "0.2"})
+
+	if _, ok := samples[gettext.PluralTypeZero]; ok {
+		t.Errorf("Expected no samples for %v, since ru doesn't use it.", gettext.PluralTypeZero)
+	}
+}
+
+func TestSamples_SearchesWhenRulesHaveNoSamples(t *testing.T) {
+	d := gettext.PluralRulesDefinition{
+		One:   "v = 0 and i % 10 = 1 and i % 100 != 11",
+		Few:   "v = 0 and i % 10 = 2..4 and i % 100 != 12..14",
+		Other: " @integer 0, 5~19",
+	}
+	rules := d.Parse()
+	samples := rules.Samples()
+
+	for _, n := range []string{"1", "21", "101"} {
+		if !slices.Contains(sampleStrings(samples[gettext.PluralTypeOne].Integers), n) {
+			t.Errorf("Expected %v in the samples of %v, got %v.", n, gettext.PluralTypeOne, samples[gettext.PluralTypeOne].Integers)
+		}
+	}
+	if slices.Contains(sampleStrings(samples[gettext.PluralTypeOne].Integers), "11") {
+		t.Errorf("Expected 11 to not be in the samples of %v.", gettext.PluralTypeOne)
+	}
+	if decimals := samples[gettext.PluralTypeFew].Decimals; len(decimals) > 0 {
+		t.Errorf("Expected no decimal samples for %v, got %v.", gettext.PluralTypeFew, decimals)
+	}
+	if integers := sampleStrings(samples[gettext.PluralTypeFew].Integers); !slices.Equal(integers[:4], []string{"2", "3", "4", "22"}) {
+		t.Errorf("Expected integer samples for %v to start with 2, 3, 4, 22, got %v.", gettext.PluralTypeFew, integers)
+	}
+
+	// other has its own samples, so isn't searched
+	expectedOther := []string{"0", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17", "18", "19"}
+	if integers := sampleStrings(samples[gettext.PluralTypeOther].Integers); !slices.Equal(expectedOther, integers) {
+		t.Errorf("Expected integer samples for %v of %v, got %v.", gettext.PluralTypeOther, expectedOther, integers)
+	}
+}
+
+func sampleStrings(samples []gettext.CompactDecimal) []string {
+	result := make([]string, len(samples))
+	for i, s := range samples {
+		result[i] = s.String()
+	}
+	return result
+}
+
+func TestSamples_SearchesForOther_WhenRulesLeaveItOut(t *testing.T) {
+	d := gettext.PluralRulesDefinition{One: "n = 1"}
+	rules := d.Parse()
+	samples := rules.Samples()
+
+	for _, pt := range rules.Categories() {
+		if _, ok := samples[pt]; !ok {
+			t.Errorf("Expected samples for %v, since it is one of the categories.", pt)
+		}
+	}
+	if integers := sampleStrings(samples[gettext.PluralTypeOther].Integers); !slices.Equal(integers[:3], []string{"0", "2", "3"}) {
+		t.Errorf("Expected other integers to start with 0, 2, 3, got %v.", integers)
+	}
+}
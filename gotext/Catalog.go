@@ -0,0 +1,91 @@
+package gotext
+
+import (
+	"fmt"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/message/catalog"
+)
+
+// contextSeparator is what gettext itself uses to join msgctxt and msgid into a single key
+const contextSeparator = "\x04"
+
+type CatalogError struct {
+	EntryKey        gettext.EntryKey
+	Reason          string
+	UnderlyingError error
+}
+
+func (e CatalogError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to add entry %+v to catalog: %v Underlying error: %v", e.EntryKey, e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to add entry %+v to catalog: %v", e.EntryKey, e.Reason)
+}
+
+// MessageKey is the key a message.Printer uses for the entry, which is the msgid,
+// or, for contextual entries, the msgctxt and msgid joined the same way gettext joins them.
+func MessageKey(key gettext.EntryKey) string {
+	if key.IsContextual {
+		return key.Context + contextSeparator + key.Id
+	}
+	return key.Id
+}
+
+// AddDocument adds the translations of the document to the builder for the document's language.
+// Plural entries become plural.Selectf cases on the first argument, with a case per plural category of the document,
+// so russian's other, which only decimals use, gets the msgstr[n] of many.
+// Like msgfmt, untranslated, fuzzy, and obsolete entries are skipped, leaving the message.Printer to fall back to the key.
+func AddDocument(b *catalog.Builder, d *gettext.Document) error {
+	plurals, err := d.Header.PluralMapping()
+	if err != nil {
+		return err
+	}
+	categories := plurals.Categories()
+
+	for _, entry := range d.Entries {
+		if len(entry.Id) == 0 || entry.IsObsolete || entry.Header.IsFuzzy() {
+			continue
+		}
+
+		key := MessageKey(entry.EntryKey)
+		if !entry.IsPlural {
+			if len(entry.Value) == 0 {
+				continue
+			}
+			if err := b.SetString(d.Header.Tag, key, entry.Value); err != nil {
+				return CatalogError{entry.EntryKey, "Unable to set string.", err}
+			}
+			continue
+		}
+
+		if err := plurals.ValidatePluralCount(&entry); err != nil {
+			return CatalogError{entry.EntryKey, "Plural values don't match the plural forms of the document.", err}
+		}
+		if !entry.IsTranslated() {
+			continue
+		}
+
+		cases := make([]interface{}, 0, len(categories)*2)
+		for _, pluralType := range categories {
+			cases = append(cases, pluralType.Category(), plurals.Value(entry.PluralValues, pluralType))
+		}
+		if err := b.Set(d.Header.Tag, key, plural.Selectf(1, "", cases...)); err != nil {
+			return CatalogError{entry.EntryKey, "Unable to set plural message.", err}
+		}
+	}
+
+	return nil
+}
+
+// NewBuilder creates a builder with the translations of each document; see AddDocument.
+func NewBuilder(documents ...*gettext.Document) (*catalog.Builder, error) {
+	b := catalog.NewBuilder()
+	for _, d := range documents {
+		if err := AddDocument(b, d); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,125 @@
+package gotext
+
+import (
+	"encoding/json"
+	"io"
+
+	"golang.org/x/text/language"
+)
+
+// Messages is the messages.gotext.json format of the gotext tool, which is golang.org/x/text/message/pipeline's Messages.
+// It is redeclared here, since the pipeline package pulls in the go tooling.
+type Messages struct {
+	Language language.Tag    `json:"language"`
+	Messages []Message       `json:"messages"`
+	Macros   map[string]Text `json:"macros,omitempty"`
+}
+
+type Message struct {
+	ID      IDList `json:"id"`
+	Key     string `json:"key,omitempty"`
+	Meaning string `json:"meaning,omitempty"`
+
+	Message     Text `json:"message"`
+	Translation Text `json:"translation"`
+
+	Comment           string `json:"comment,omitempty"`
+	TranslatorComment string `json:"translatorComment,omitempty"`
+
+	Placeholders []Placeholder `json:"placeholders,omitempty"`
+
+	Fuzzy    bool   `json:"fuzzy,omitempty"`
+	Position string `json:"position,omitempty"`
+}
+
+// IDList is one or more ids, where the first is the one the message is looked up by.
+type IDList []string
+
+type Placeholder struct {
+	ID             string `json:"id"`
+	String         string `json:"string"`
+	Type           string `json:"type"`
+	UnderlyingType string `json:"underlyingType"`
+	ArgNum         int    `json:"argNum"`
+	Expr           string `json:"expr"`
+	Comment        string `json:"comment,omitempty"`
+	Example        string `json:"example,omitempty"`
+
+	Features []Feature `json:"features,omitempty"`
+}
+
+type Feature struct {
+	Type string `json:"type"`
+}
+
+// Text is either a plain message or a select, like a plural select. In json, plain messages are just strings.
+type Text struct {
+	Msg     string          `json:"msg,omitempty"`
+	Select  *Select         `json:"select,omitempty"`
+	Var     map[string]Text `json:"var,omitempty"`
+	Example string          `json:"example,omitempty"`
+}
+
+type Select struct {
+	Feature string          `json:"feature"`
+	Arg     string          `json:"arg"`
+	Cases   map[string]Text `json:"cases,omitempty"`
+}
+
+func ParseMessages(r io.Reader) (Messages, error) {
+	var m Messages
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return Messages{}, err
+	}
+	return m, nil
+}
+
+// WriteTo writes the messages as indented json, like the gotext tool does.
+func (m *Messages) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+func (l IDList) MarshalJSON() ([]byte, error) {
+	if len(l) == 1 {
+		return json.Marshal(l[0])
+	}
+	return json.Marshal([]string(l))
+}
+
+func (l *IDList) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var id string
+		if err := json.Unmarshal(data, &id); err != nil {
+			return err
+		}
+		*l = IDList{id}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+func (t Text) MarshalJSON() ([]byte, error) {
+	if t.Select == nil && t.Var == nil && len(t.Example) == 0 {
+		return json.Marshal(t.Msg)
+	}
+
+	// an alias, so that marshaling doesn't call this method again
+	type text Text
+	return json.Marshal(text(t))
+}
+
+func (t *Text) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*t = Text{}
+		return json.Unmarshal(data, &t.Msg)
+	}
+
+	type text Text
+	return json.Unmarshal(data, (*text)(t))
+}
@@ -0,0 +1,260 @@
+package gotext
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+// what messages.gotext.json has that po files don't is kept in extracted comments with these prefixes,
+// so that converting a document back gives the same messages
+const (
+	idCommentPrefix          = "gotext-id: "
+	keyCommentPrefix         = "gotext-key: "
+	messageCommentPrefix     = "gotext-message: "
+	placeholderCommentPrefix = "gotext-placeholder: "
+	pluralArgCommentPrefix   = "gotext-plural-arg: "
+)
+
+type MessageConversionError struct {
+	ID              string
+	Reason          string
+	UnderlyingError error
+}
+
+func (e MessageConversionError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to convert message '%v': %v Underlying error: %v", e.ID, e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to convert message '%v': %v", e.ID, e.Reason)
+}
+
+// DocumentFromMessages converts messages to a document, where a message's first id is the msgid and its meaning is the msgctxt.
+// Plural selects become plural entries, with the msgstr[n] of gettext.DefaultPluralMapping for the language.
+// Placeholders and other things po files have no place for are kept as "#. gotext-*: " comments.
+// Other kinds of selects, vars, and macros aren't supported.
+func DocumentFromMessages(m *Messages) (gettext.Document, error) {
+	// only needed for plural messages, so that languages without plural rules can have other messages
+	plurals := sync.OnceValues(func() (gettext.PluralMapping, error) {
+		return gettext.DefaultPluralMapping(m.Language)
+	})
+
+	entries := make([]gettext.Entry, 0, len(m.Messages))
+	for _, message := range m.Messages {
+		if len(message.ID) == 0 {
+			return gettext.Document{}, MessageConversionError{Reason: "Message has no id."}
+		}
+		id := message.ID[0]
+
+		header, err := createEntryHeader(&message)
+		if err != nil {
+			return gettext.Document{}, err
+		}
+		key := gettext.EntryKey{
+			IsContextual: len(message.Meaning) > 0,
+			Context:      message.Meaning,
+			Id:           id,
+		}
+
+		if message.Translation.Var != nil || message.Message.Var != nil {
+			return gettext.Document{}, MessageConversionError{ID: id, Reason: "Vars are not supported."}
+		}
+
+		pluralSelect := message.Translation.Select
+		if pluralSelect == nil && len(message.Translation.Msg) == 0 {
+			// untranslated plural messages
+			pluralSelect = message.Message.Select
+		}
+		if pluralSelect == nil {
+			entries = append(entries, gettext.NewEntry(key, header, message.Translation.Msg, nil))
+			continue
+		}
+		if pluralSelect.Feature != "plural" {
+			return gettext.Document{}, MessageConversionError{ID: id, Reason: fmt.Sprint("Only plural selects are supported. Found: ", pluralSelect.Feature)}
+		}
+
+		plurals, err := plurals()
+		if err != nil {
+			return gettext.Document{}, MessageConversionError{id, "Plural rules for the language are needed for plural messages.", err}
+		}
+
+		key.IsPlural = true
+		key.PluralId = pluralId(&message)
+		header.ExtractedComments = append(header.ExtractedComments, pluralArgCommentPrefix+pluralSelect.Arg)
+
+		pluralValues, err := pluralValuesFromSelect(id, message.Translation.Select, &plurals)
+		if err != nil {
+			return gettext.Document{}, err
+		}
+		entries = append(entries, gettext.NewEntry(key, header, "", pluralValues))
+	}
+
+	return gettext.NewDocument(m.Language, entries)
+}
+
+func createEntryHeader(message *Message) (gettext.EntryHeader, error) {
+	var header gettext.EntryHeader
+
+	if len(message.TranslatorComment) > 0 {
+		header.TranslatorComments = append(header.TranslatorComments, message.TranslatorComment)
+	}
+	if len(message.Comment) > 0 {
+		header.ExtractedComments = append(header.ExtractedComments, message.Comment)
+	}
+	for _, id := range message.ID[1:] {
+		header.ExtractedComments = append(header.ExtractedComments, idCommentPrefix+id)
+	}
+	if len(message.Key) > 0 {
+		header.ExtractedComments = append(header.ExtractedComments, keyCommentPrefix+message.Key)
+	}
+
+	// the message is usually the same as the id, so is only kept when it isn't
+	if message.Message.Select != nil || message.Message.Msg != message.ID[0] {
+		b, err := json.Marshal(message.Message)
+		if err != nil {
+			return gettext.EntryHeader{}, MessageConversionError{message.ID[0], "Unable to keep message.", err}
+		}
+		header.ExtractedComments = append(header.ExtractedComments, messageCommentPrefix+string(b))
+	}
+	for _, placeholder := range message.Placeholders {
+		b, err := json.Marshal(placeholder)
+		if err != nil {
+			return gettext.EntryHeader{}, MessageConversionError{message.ID[0], "Unable to keep placeholder.", err}
+		}
+		header.ExtractedComments = append(header.ExtractedComments, placeholderCommentPrefix+string(b))
+	}
+
+	if len(message.Position) > 0 {
+		header.References = append(header.References, message.Position)
+	}
+	if message.Fuzzy {
+		header.Flags = append(header.Flags, "fuzzy")
+	}
+
+	return header, nil
+}
+
+// pluralId is the other case of the source message, if it is a plural select, since that is the usual plural form of english
+func pluralId(message *Message) string {
+	if s := message.Message.Select; s != nil {
+		if other, ok := s.Cases["other"]; ok && len(other.Msg) > 0 {
+			return other.Msg
+		}
+	}
+	if len(message.Message.Msg) > 0 {
+		return message.Message.Msg
+	}
+	return message.ID[0]
+}
+
+func pluralValuesFromSelect(id string, s *Select, plurals *gettext.PluralMapping) ([]string, error) {
+	if s == nil {
+		return make([]string, plurals.NPlurals), nil
+	}
+
+	values := make(map[gettext.PluralType]string, len(s.Cases))
+	for category, text := range s.Cases {
+		pluralType, ok := gettext.PluralTypeFromCategory(category)
+		if !ok {
+			return nil, MessageConversionError{ID: id, Reason: fmt.Sprint("Only plural categories are supported as cases. Found: ", category)}
+		}
+		if text.Select != nil || text.Var != nil {
+			return nil, MessageConversionError{ID: id, Reason: "Nested selects and vars are not supported."}
+		}
+		if _, ok := plurals.Index(pluralType); !ok {
+			return nil, MessageConversionError{ID: id, Reason: fmt.Sprint("The language does not use the plural category: ", category)}
+		}
+		values[pluralType] = text.Msg
+	}
+
+	return plurals.PluralValues(func(pluralType gettext.PluralType) string { return values[pluralType] }), nil
+}
+
+// MessagesFromDocument converts a document to messages; see DocumentFromMessages.
+// Plural entries become plural selects, with a case per plural category of the document.
+// Obsolete entries are skipped.
+func MessagesFromDocument(d *gettext.Document) (Messages, error) {
+	result := Messages{Language: d.Header.Tag}
+
+	plurals := sync.OnceValues(d.Header.PluralMapping)
+	for _, entry := range d.Entries {
+		if len(entry.Id) == 0 || entry.IsObsolete {
+			continue
+		}
+
+		message := Message{
+			ID:                IDList{entry.Id},
+			Meaning:           entry.Context,
+			Message:           Text{Msg: entry.Id},
+			TranslatorComment: strings.Join(entry.Header.TranslatorComments, "\n"),
+			Position:          strings.Join(entry.Header.References, " "),
+			Fuzzy:             entry.Header.IsFuzzy(),
+		}
+
+		var comments []string
+		pluralArg := ""
+		for _, comment := range entry.Header.ExtractedComments {
+			var err error
+			switch {
+			case strings.HasPrefix(comment, idCommentPrefix):
+				message.ID = append(message.ID, strings.TrimPrefix(comment, idCommentPrefix))
+			case strings.HasPrefix(comment, keyCommentPrefix):
+				message.Key = strings.TrimPrefix(comment, keyCommentPrefix)
+			case strings.HasPrefix(comment, messageCommentPrefix):
+				err = json.Unmarshal([]byte(strings.TrimPrefix(comment, messageCommentPrefix)), &message.Message)
+			case strings.HasPrefix(comment, placeholderCommentPrefix):
+				var placeholder Placeholder
+				err = json.Unmarshal([]byte(strings.TrimPrefix(comment, placeholderCommentPrefix)), &placeholder)
+				message.Placeholders = append(message.Placeholders, placeholder)
+			case strings.HasPrefix(comment, pluralArgCommentPrefix):
+				pluralArg = strings.TrimPrefix(comment, pluralArgCommentPrefix)
+			default:
+				comments = append(comments, comment)
+			}
+
+			if err != nil {
+				return Messages{}, MessageConversionError{entry.Id, fmt.Sprint("Unable to read comment: ", comment), err}
+			}
+		}
+		message.Comment = strings.Join(comments, "\n")
+
+		if !entry.IsPlural {
+			message.Translation = Text{Msg: entry.Value}
+			result.Messages = append(result.Messages, message)
+			continue
+		}
+
+		plurals, err := plurals()
+		if err != nil {
+			return Messages{}, MessageConversionError{entry.Id, "Plural rules for the language are needed for plural entries.", err}
+		}
+		if err := plurals.ValidatePluralCount(&entry); err != nil {
+			return Messages{}, MessageConversionError{entry.Id, "Plural values don't match the plural forms of the document.", err}
+		}
+
+		if len(pluralArg) == 0 && len(message.Placeholders) > 0 {
+			pluralArg = message.Placeholders[0].ID
+		}
+		message.Translation = translationFromPluralValues(&entry, pluralArg, &plurals)
+		result.Messages = append(result.Messages, message)
+	}
+
+	return result, nil
+}
+
+func translationFromPluralValues(entry *gettext.Entry, arg string, plurals *gettext.PluralMapping) Text {
+	if !entry.IsTranslated() {
+		// like gotext, untranslated messages have an empty translation
+		return Text{}
+	}
+
+	categories := plurals.Categories()
+	cases := make(map[string]Text, len(categories))
+	for _, pluralType := range categories {
+		cases[pluralType.Category()] = Text{Msg: plurals.Value(entry.PluralValues, pluralType)}
+	}
+	return Text{Select: &Select{Feature: "plural", Arg: arg, Cases: cases}}
+}
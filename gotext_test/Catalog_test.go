@@ -0,0 +1,86 @@
+package gotext_test
+
+import (
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/gotext"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const documentText = `
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+
+msgid "Open"
+msgstr "Открыть"
+
+msgctxt "verb"
+msgid "Open"
+msgstr "Открывать"
+
+#, fuzzy
+msgid "Close"
+msgstr "Закрыть"
+
+msgid "Save"
+msgstr ""`
+
+func TestAddDocument(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	b, err := gotext.NewBuilder(&doc)
+	if err != nil {
+		t.Fatal("Error building catalog: ", err)
+	}
+	p := message.NewPrinter(language.Russian, message.Catalog(b))
+
+	verify := func(expected string, key string, args ...interface{}) {
+		if s := p.Sprintf(key, args...); s != expected {
+			t.Errorf("Expected '%v' for key '%v', got '%v'.", expected, key, s)
+		}
+	}
+
+	verify("1 файл", "%d file", 1)
+	verify("3 файла", "%d file", 3)
+	verify("5 файлов", "%d file", 5)
+	verify("21 файл", "%d file", 21)
+	verify("11 файлов", "%d file", 11)
+	verify("Открыть", "Open")
+	verify("Открывать", gotext.MessageKey(gettext.EntryKey{IsContextual: true, Context: "verb", Id: "Open"}))
+
+	// fuzzy and untranslated entries fall back to the key
+	verify("Close", "Close")
+	verify("Save", "Save")
+}
+
+func TestAddDocument_ReturnsError_WhenPluralCountWrong(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`
+msgid ""
+msgstr "Language: ru\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файлов"`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	_, err = gotext.NewBuilder(&doc)
+	if _, ok := err.(gotext.CatalogError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gotext.CatalogError{}, err, err)
+	}
+}
@@ -0,0 +1,138 @@
+package gotext_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/gotext"
+)
+
+const messagesText = `{
+    "language": "ru",
+    "messages": [
+        {
+            "id": "Hello {City}!",
+            "message": "Hello {City}!",
+            "translation": "Привет, {City}!",
+            "comment": "Greets the city",
+            "translatorComment": "Copied from source.",
+            "placeholders": [
+                {
+                    "id": "City",
+                    "string": "%[1]s",
+                    "type": "string",
+                    "underlyingType": "string",
+                    "argNum": 1,
+                    "expr": "city"
+                }
+            ],
+            "fuzzy": true,
+            "position": "main.go:12:2"
+        },
+        {
+            "id": "{N} files",
+            "message": "{N} files",
+            "translation": {
+                "select": {
+                    "feature": "plural",
+                    "arg": "N",
+                    "cases": {
+                        "few": "{N} файла",
+                        "many": "{N} файлов",
+                        "one": "{N} файл",
+                        "other": "{N} файла"
+                    }
+                }
+            },
+            "placeholders": [
+                {
+                    "id": "N",
+                    "string": "%[1]d",
+                    "type": "int",
+                    "underlyingType": "int",
+                    "argNum": 1,
+                    "expr": "n"
+                }
+            ]
+        },
+        {
+            "id": [
+                "Open",
+                "msgOpen"
+            ],
+            "meaning": "verb",
+            "message": "Open",
+            "translation": ""
+        }
+    ]
+}
+`
+
+func TestMessages_RoundTripThroughDocument(t *testing.T) {
+	messages, err := gotext.ParseMessages(strings.NewReader(messagesText))
+	if err != nil {
+		t.Fatal("Error parsing messages: ", err)
+	}
+
+	doc, err := gotext.DocumentFromMessages(&messages)
+	if err != nil {
+		t.Fatal("Error converting messages: ", err)
+	}
+
+	var poText strings.Builder
+	if _, err := doc.WriteTo(&poText); err != nil {
+		t.Fatal("Error writing document: ", err)
+	}
+	parsed, err := gettext.ParseDocumentString(poText.String())
+	if err != nil {
+		t.Fatalf("Error parsing document: %v\n%v", err, poText.String())
+	}
+
+	plural := parsed.Entries[2]
+	expectedPluralValues := []string{"{N} файл", "{N} файла", "{N} файлов"}
+	if !plural.IsPlural || strings.Join(plural.PluralValues, "|") != strings.Join(expectedPluralValues, "|") {
+		t.Errorf("Expected plural values %v, got %+v.", expectedPluralValues, plural)
+	}
+	if contextual := parsed.Entries[3]; !contextual.IsContextual || contextual.Context != "verb" {
+		t.Errorf("Expected the meaning to be the context, got %+v.", contextual.EntryKey)
+	}
+	if !parsed.Entries[1].Header.IsFuzzy() {
+		t.Error("Expected entry to be fuzzy.")
+	}
+
+	converted, err := gotext.MessagesFromDocument(&parsed)
+	if err != nil {
+		t.Fatal("Error converting document: ", err)
+	}
+	var messagesBuffer bytes.Buffer
+	if _, err := converted.WriteTo(&messagesBuffer); err != nil {
+		t.Fatal("Error writing messages: ", err)
+	}
+	// the Plural-Forms of russian give other, which is only for decimals, the msgstr[n] of many
+	expectedMessagesText := strings.Replace(messagesText, `"other": "{N} файла"`, `"other": "{N} файлов"`, 1)
+	if messagesBuffer.String() != expectedMessagesText {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedMessagesText, messagesBuffer.String())
+	}
+}
+
+func TestDocumentFromMessages_ReturnsError_WhenCaseNotPluralCategory(t *testing.T) {
+	messages := gotext.Messages{}
+	if err := json.Unmarshal([]byte(`{
+    "language": "ru",
+    "messages": [{
+        "id": "{N} files",
+        "message": "{N} files",
+        "translation": {"select": {"feature": "plural", "arg": "N", "cases": {"=0": "нет файлов", "other": "{N} файла"}}}
+    }]
+}`), &messages); err != nil {
+		t.Fatal("Error parsing messages: ", err)
+	}
+
+	_, err := gotext.DocumentFromMessages(&messages)
+	if _, ok := err.(gotext.MessageConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", gotext.MessageConversionError{}, err, err)
+	}
+}
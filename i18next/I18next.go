@@ -0,0 +1,344 @@
+package i18next
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+// the i18next defaults, which are also what i18next v4 json uses for plurals
+const (
+	keySeparator    = "."
+	pluralSeparator = "_"
+)
+
+type Options struct {
+	// Flat keeps keys like "menu.open" as they are, instead of nesting them as {"menu": {"open": ...}}.
+	Flat bool
+
+	// IncludeFuzzy also writes fuzzy entries.
+	IncludeFuzzy bool
+}
+
+type ConversionError struct {
+	Key    string
+	Reason string
+}
+
+func (e ConversionError) Error() string {
+	return fmt.Sprintf("Failed to convert '%v': %v", e.Key, e.Reason)
+}
+
+// Write writes the document as i18next v4 json, where the msgctxt is the key, or the msgid for entries without one.
+// Keys from msgctxts are nested on ".", unless Options.Flat, and msgids, which are usually sentences, are never split, so need i18next's keySeparator turned off.
+// i18next contexts, like "friend_male", are part of the key, so come from the msgctxt too.
+// Plural entries have a key per plural category of the document, like "file_one" and "file_other".
+// Untranslated and obsolete entries are skipped, so that i18next falls back to another language.
+func Write(w io.Writer, d *gettext.Document, options Options) error {
+	plurals := sync.OnceValues(d.Header.PluralMapping)
+	root := &jsonObject{}
+
+	for _, entry := range d.Entries {
+		if len(entry.Id) == 0 || entry.IsObsolete || (entry.Header.IsFuzzy() && !options.IncludeFuzzy) {
+			continue
+		}
+
+		key, nest := entry.Id, false
+		if entry.IsContextual {
+			key, nest = entry.Context, !options.Flat
+		}
+
+		if !entry.IsPlural {
+			if len(entry.Value) > 0 {
+				if err := root.add(key, entry.Value, nest); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		plurals, err := plurals()
+		if err != nil {
+			return err
+		}
+		if err := plurals.ValidatePluralCount(&entry); err != nil {
+			return err
+		}
+
+		for _, pluralType := range plurals.Categories() {
+			if value := plurals.Value(entry.PluralValues, pluralType); len(value) > 0 {
+				if err := root.add(key+pluralSeparator+pluralType.Category(), value, nest); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// so that html in values stays readable
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	return e.Encode(root)
+}
+
+// resource is a key, or a key with plural suffixes, like "file_one" and "file_other"
+type resource struct {
+	key      string
+	isPlural bool
+	value    string
+	// keyed by category
+	pluralValues map[string]string
+}
+
+// Read reads i18next v4 json of translations, nested or flat, along with the json of the source language, which may be nil for apps that use source strings as keys.
+// With a source json, keys become the msgctxt, unless they're the same as the source string, which becomes the msgid.
+// Keys with a plural category suffix, like "file_one", are plural entries, where the one and other keys of the source become the msgid and msgid_plural.
+func Read(source io.Reader, translations io.Reader, tag language.Tag) (gettext.Document, error) {
+	plurals, err := gettext.DefaultPluralMapping(tag)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+	categories := plurals.Categories()
+
+	var translatedResources []*resource
+	if translations != nil {
+		if translatedResources, err = readResources(translations, categories); err != nil {
+			return gettext.Document{}, err
+		}
+	}
+
+	if source == nil {
+		entries := make([]gettext.Entry, 0, len(translatedResources))
+		for _, res := range translatedResources {
+			key := gettext.EntryKey{Id: res.key}
+			if res.isPlural {
+				key.IsPlural, key.PluralId = true, res.key
+			}
+			entries = append(entries, createEntry(key, res, res, &plurals))
+		}
+		return gettext.NewDocument(tag, entries)
+	}
+
+	// the source language isn't known, so any category is a plural suffix
+	sourceResources, err := readResources(source, allCategories)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+	translatedByKey := make(map[string]*resource, len(translatedResources))
+	for _, res := range translatedResources {
+		translatedByKey[res.key] = res
+	}
+
+	entries := make([]gettext.Entry, 0, len(sourceResources))
+	for _, res := range sourceResources {
+		translated, ok := translatedByKey[res.key]
+		if !ok || translated.isPlural != res.isPlural {
+			translated = &resource{}
+		}
+
+		key := gettext.EntryKey{Id: res.value}
+		if res.isPlural {
+			key.IsPlural, key.Id, key.PluralId = true, res.pluralValue("one"), res.pluralValue("other")
+		}
+		if key.Id != res.key {
+			key.IsContextual, key.Context = true, res.key
+		}
+		entries = append(entries, createEntry(key, res, translated, &plurals))
+	}
+	return gettext.NewDocument(tag, entries)
+}
+
+var allCategories = []gettext.PluralType{
+	gettext.PluralTypeZero, gettext.PluralTypeOne, gettext.PluralTypeTwo, gettext.PluralTypeFew, gettext.PluralTypeMany, gettext.PluralTypeOther,
+}
+
+// pluralValue falls back to the other category, which every language has
+func (res *resource) pluralValue(category string) string {
+	if value, ok := res.pluralValues[category]; ok {
+		return value
+	}
+	return res.pluralValues["other"]
+}
+
+func createEntry(key gettext.EntryKey, source *resource, translated *resource, plurals *gettext.PluralMapping) gettext.Entry {
+	if !source.isPlural {
+		return gettext.NewEntry(key, gettext.EntryHeader{}, translated.value, nil)
+	}
+
+	values := plurals.PluralValues(func(pluralType gettext.PluralType) string {
+		return translated.pluralValues[pluralType.Category()]
+	})
+	return gettext.NewEntry(key, gettext.EntryHeader{}, "", values)
+}
+
+// readResources groups plural keys by the key without the plural suffix, keeping the order keys were first seen in
+func readResources(r io.Reader, categories []gettext.PluralType) ([]*resource, error) {
+	values, err := readFlattened(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*resource
+	resourcesByKey := make(map[string]*resource)
+	getResource := func(key string, isPlural bool) *resource {
+		if res, ok := resourcesByKey[key]; ok && res.isPlural == isPlural {
+			return res
+		}
+		res := &resource{key: key, isPlural: isPlural}
+		if isPlural {
+			res.pluralValues = make(map[string]string)
+		}
+		resources = append(resources, res)
+		resourcesByKey[key] = res
+		return res
+	}
+
+	for _, kv := range values {
+		if key, pluralType, ok := splitPluralSuffix(kv.key, categories); ok {
+			getResource(key, true).pluralValues[pluralType.Category()] = kv.value
+			continue
+		}
+		getResource(kv.key, false).value = kv.value
+	}
+	return resources, nil
+}
+
+func splitPluralSuffix(key string, categories []gettext.PluralType) (string, gettext.PluralType, bool) {
+	i := strings.LastIndex(key, pluralSeparator)
+	if i <= 0 {
+		return key, gettext.PluralTypeOther, false
+	}
+
+	pluralType, ok := gettext.PluralTypeFromCategory(key[i+1:])
+	if !ok || !slices.Contains(categories, pluralType) {
+		return key, gettext.PluralTypeOther, false
+	}
+	return key[:i], pluralType, true
+}
+
+type keyValue struct {
+	key, value string
+}
+
+// readFlattened reads the json in order, joining nested keys with the key separator
+func readFlattened(r io.Reader) ([]keyValue, error) {
+	decoder := json.NewDecoder(r)
+	var result []keyValue
+
+	var readObject func(prefix string) error
+	readObject = func(prefix string) error {
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := prefix + token.(string)
+
+			token, err = decoder.Token()
+			if err != nil {
+				return err
+			}
+			switch value := token.(type) {
+			case string:
+				result = append(result, keyValue{key, value})
+			case json.Delim:
+				if value != '{' {
+					return ConversionError{key, "Arrays are not supported."}
+				}
+				if err := readObject(key + keySeparator); err != nil {
+					return err
+				}
+			default:
+				return ConversionError{key, fmt.Sprintf("Only strings and objects are supported. Found: %v", value)}
+			}
+		}
+
+		// the closing }
+		_, err := decoder.Token()
+		return err
+	}
+
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, ConversionError{Reason: "Expected a json object."}
+	}
+	if err := readObject(""); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// jsonObject keeps the order keys were added in, unlike maps
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *jsonObject) add(key string, value string, nest bool) error {
+	if !nest {
+		return o.set(key, key, value)
+	}
+
+	parts := strings.Split(key, keySeparator)
+	current := o
+	for _, part := range parts[:len(parts)-1] {
+		existing, ok := current.values[part]
+		if !ok {
+			child := &jsonObject{}
+			current.set(key, part, child)
+			current = child
+			continue
+		}
+
+		child, ok := existing.(*jsonObject)
+		if !ok {
+			return ConversionError{key, fmt.Sprintf("Unable to nest under '%v', since it is already a string.", part)}
+		}
+		current = child
+	}
+
+	return current.set(key, parts[len(parts)-1], value)
+}
+
+func (o *jsonObject) set(fullKey string, key string, value any) error {
+	if _, ok := o.values[key]; ok {
+		return ConversionError{fullKey, "Key found more than once."}
+	}
+	if o.values == nil {
+		o.values = make(map[string]any)
+	}
+
+	o.keys = append(o.keys, key)
+	o.values[key] = value
+	return nil
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		e := json.NewEncoder(&buf)
+		e.SetEscapeHTML(false)
+		if err := e.Encode(key); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := e.Encode(o.values[key]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
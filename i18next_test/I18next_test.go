@@ -0,0 +1,166 @@
+package i18next_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/i18next"
+	"golang.org/x/text/language"
+)
+
+const documentText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgctxt "menu.open"
+msgid "Open"
+msgstr "<b>Открыть</b>"
+
+msgctxt "menu.close"
+msgid "Close"
+msgstr "Закрыть"
+
+msgid "File saved."
+msgstr "Файл сохранён."
+
+msgid "File saved"
+msgstr "Файл сохранён"
+
+msgctxt "friend_female"
+msgid "Friend"
+msgstr "Подруга"
+
+msgctxt "file"
+msgid "{{count}} file"
+msgid_plural "{{count}} files"
+msgstr[0] "{{count}} файл"
+msgstr[1] "{{count}} файла"
+msgstr[2] "{{count}} файлов"
+
+#, fuzzy
+msgctxt "greeting"
+msgid "Hello, {{name, uppercase}}!"
+msgstr "Привет, {{name, uppercase}}!"
+
+msgctxt "help"
+msgid "Use $t(menu.open) to open a file."
+msgstr ""`
+
+const expectedJson = `{
+  "menu": {
+    "open": "<b>Открыть</b>",
+    "close": "Закрыть"
+  },
+  "File saved.": "Файл сохранён.",
+  "File saved": "Файл сохранён",
+  "friend_female": "Подруга",
+  "file_one": "{{count}} файл",
+  "file_few": "{{count}} файла",
+  "file_many": "{{count}} файлов",
+  "file_other": "{{count}} файлов"
+}
+`
+
+const sourceJson = `{
+  "menu": {
+    "open": "Open",
+    "close": "Close"
+  },
+  "File saved.": "File saved.",
+  "File saved": "File saved",
+  "friend_female": "Friend",
+  "file_one": "{{count}} file",
+  "file_other": "{{count}} files",
+  "greeting": "Hello, {{name, uppercase}}!",
+  "help": "Use $t(menu.open) to open a file."
+}
+`
+
+func TestWrite(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := i18next.Write(&buf, &doc, i18next.Options{}); err != nil {
+		t.Fatal("Error writing json: ", err)
+	}
+	if buf.String() != expectedJson {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedJson, buf.String())
+	}
+
+	buf.Reset()
+	if err := i18next.Write(&buf, &doc, i18next.Options{Flat: true, IncludeFuzzy: true}); err != nil {
+		t.Fatal("Error writing json: ", err)
+	}
+	for _, s := range []string{`"menu.open": "<b>Открыть</b>"`, `"File saved.": "Файл сохранён."`, `"greeting": "Привет, {{name, uppercase}}!"`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected %v in:\n%v", s, buf.String())
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	doc, err := i18next.Read(strings.NewReader(sourceJson), strings.NewReader(expectedJson), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading json: ", err)
+	}
+
+	expected, _ := gettext.ParseDocumentString(documentText)
+	expectedEntries := expected.Entries[1:]
+	actualEntries := doc.Entries[1:]
+	if len(actualEntries) != len(expectedEntries) {
+		t.Fatalf("Expected %v entries, got %v.", len(expectedEntries), len(actualEntries))
+	}
+	for i, e := range expectedEntries {
+		a := actualEntries[i]
+		// fuzzy entries weren't written
+		expectedValue := e.Value
+		if e.Header.IsFuzzy() {
+			expectedValue = ""
+		}
+		if a.EntryKey != e.EntryKey || a.Value != expectedValue || !slices.Equal(a.PluralValues, e.PluralValues) {
+			t.Errorf("Expected entry %+v %v %v, got %+v %v %v.", e.EntryKey, expectedValue, e.PluralValues, a.EntryKey, a.Value, a.PluralValues)
+		}
+	}
+}
+
+func TestRead_UsesKeysAsMsgids_WhenNoSource(t *testing.T) {
+	doc, err := i18next.Read(nil, strings.NewReader(`{"File saved.": "Файл сохранён.", "file_one": "{{count}} файл"}`), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading json: ", err)
+	}
+
+	if entry := doc.Entries[1]; entry.Id != "File saved." || entry.IsContextual || entry.Value != "Файл сохранён." {
+		t.Errorf("Expected the key as the msgid, got %+v %v.", entry.EntryKey, entry.Value)
+	}
+	if entry := doc.Entries[2]; entry.Id != "file" || !entry.IsPlural || entry.PluralValues[0] != "{{count}} файл" {
+		t.Errorf("Expected a plural entry, got %+v %v.", entry.EntryKey, entry.PluralValues)
+	}
+}
+
+func TestRead_TreatsSuffixAsPartOfKey_WhenNotPluralCategory(t *testing.T) {
+	doc, err := i18next.Read(nil, strings.NewReader(`{"user_name": "Имя", "page_two": "Вторая"}`), language.Russian)
+	if err != nil {
+		t.Fatal("Error reading json: ", err)
+	}
+
+	// ru doesn't have a two plural category
+	for i, id := range []string{"user_name", "page_two"} {
+		if entry := doc.Entries[i+1]; entry.Id != id || entry.IsContextual || entry.IsPlural {
+			t.Errorf("Expected plain entry %v, got %+v.", id, entry.EntryKey)
+		}
+	}
+}
+
+func TestRead_ReturnsError_WhenArrayFound(t *testing.T) {
+	_, err := i18next.Read(nil, strings.NewReader(`{"list": ["a", "b"]}`), language.Russian)
+	if _, ok := err.(i18next.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", i18next.ConversionError{}, err, err)
+	}
+}
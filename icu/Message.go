@@ -0,0 +1,150 @@
+package icu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Message is a parsed ICU MessageFormat message, which is a sequence of text, arguments, plurals, and selects.
+type Message []Node
+
+// Node is one of Text, Pound, Argument, Plural, or Select.
+type Node interface {
+	write(sb *strings.Builder, inPlural bool)
+}
+
+// Text is literal text, with quoting already removed.
+type Text string
+
+// Pound is the # of a plural case, which is the number, minus the offset.
+type Pound struct{}
+
+// Argument is a simple argument like {name}, or a formatted one like {count, number, integer}.
+type Argument struct {
+	Name  string
+	Type  string
+	Style string
+}
+
+// Plural is a plural or selectordinal argument, like {count, plural, one {# file} other {# files}}.
+type Plural struct {
+	Argument string
+	Ordinal  bool
+	Offset   int
+	Cases    []Case
+}
+
+// Select is a select argument, like {gender, select, female {she} other {they}}.
+type Select struct {
+	Argument string
+	Cases    []Case
+}
+
+// Case is a case of a plural or select, where plural selectors are either plural categories, like "one", or explicit values, like "=0".
+type Case struct {
+	Selector string
+	Message  Message
+}
+
+// Case finds the case with the selector.
+func (p *Plural) Case(selector string) (Message, bool) {
+	return findCase(p.Cases, selector)
+}
+
+// Case finds the case with the selector.
+func (s *Select) Case(selector string) (Message, bool) {
+	return findCase(s.Cases, selector)
+}
+
+func findCase(cases []Case, selector string) (Message, bool) {
+	for _, c := range cases {
+		if c.Selector == selector {
+			return c.Message, true
+		}
+	}
+	return nil, false
+}
+
+// String writes the message back as ICU MessageFormat, quoting text where needed.
+func (m Message) String() string {
+	var sb strings.Builder
+	m.write(&sb, false)
+	return sb.String()
+}
+
+func (m Message) write(sb *strings.Builder, inPlural bool) {
+	for _, node := range m {
+		node.write(sb, inPlural)
+	}
+}
+
+func (t Text) write(sb *strings.Builder, inPlural bool) {
+	runes := []rune(t)
+	for i, r := range runes {
+		switch {
+		case r == '\'':
+			// an apostrophe is only special before what gets quoted, which includes whatever comes after the text,
+			// so other apostrophes, like the one in "l'heure", are written as they are
+			if i+1 == len(runes) || isQuotable(runes[i+1], inPlural) || runes[i+1] == '\'' {
+				sb.WriteString("''")
+			} else {
+				sb.WriteRune(r)
+			}
+		case r == '{' || r == '}' || (r == '#' && inPlural):
+			sb.WriteString("'")
+			sb.WriteRune(r)
+			sb.WriteString("'")
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// isQuotable is whether an apostrophe before the character starts quoted text
+func isQuotable(r rune, inPlural bool) bool {
+	return r == '{' || r == '}' || r == '|' || (r == '#' && inPlural)
+}
+
+func (p Pound) write(sb *strings.Builder, inPlural bool) {
+	sb.WriteString("#")
+}
+
+func (a Argument) write(sb *strings.Builder, inPlural bool) {
+	sb.WriteString("{")
+	sb.WriteString(a.Name)
+	if len(a.Type) > 0 {
+		sb.WriteString(", ")
+		sb.WriteString(a.Type)
+	}
+	if len(a.Style) > 0 {
+		sb.WriteString(", ")
+		sb.WriteString(a.Style)
+	}
+	sb.WriteString("}")
+}
+
+func (p Plural) write(sb *strings.Builder, inPlural bool) {
+	kind := "plural"
+	if p.Ordinal {
+		kind = "selectordinal"
+	}
+	fmt.Fprintf(sb, "{%v, %v,", p.Argument, kind)
+	if p.Offset != 0 {
+		fmt.Fprintf(sb, " offset:%v", p.Offset)
+	}
+	writeCases(sb, p.Cases, true)
+}
+
+func (s Select) write(sb *strings.Builder, inPlural bool) {
+	fmt.Fprintf(sb, "{%v, select,", s.Argument)
+	writeCases(sb, s.Cases, inPlural)
+}
+
+func writeCases(sb *strings.Builder, cases []Case, inPlural bool) {
+	for _, c := range cases {
+		fmt.Fprintf(sb, " %v {", c.Selector)
+		c.Message.write(sb, inPlural)
+		sb.WriteString("}")
+	}
+	sb.WriteString("}")
+}
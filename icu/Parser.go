@@ -0,0 +1,276 @@
+package icu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type ParseError struct {
+	Message string
+	Offset  int
+	Reason  string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("Failed to parse ICU message at offset %v: %v Message: %v", e.Offset, e.Reason, e.Message)
+}
+
+// Parse parses an ICU MessageFormat message, with the apostrophe quoting of ICU 4.8 and later,
+// where an apostrophe only starts quoted text before {, }, a # in a plural, or another apostrophe.
+func Parse(s string) (Message, error) {
+	p := parser{s: s}
+	m, err := p.parseMessage(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.error("Found an unmatched '}'.")
+	}
+	return m, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+// parseMessage parses until the end of the string, or the } that ends the case the message is in
+func (p *parser) parseMessage(inPlural bool) (Message, error) {
+	var m Message
+	var text strings.Builder
+	flushText := func() {
+		if text.Len() > 0 {
+			m = append(m, Text(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\'':
+			p.pos++
+			if err := p.readQuoted(&text, inPlural); err != nil {
+				return nil, err
+			}
+		case c == '{':
+			flushText()
+			node, err := p.parseArgument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, node)
+		case c == '}':
+			flushText()
+			return m, nil
+		case c == '#' && inPlural:
+			flushText()
+			m = append(m, Pound{})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	flushText()
+	return m, nil
+}
+
+// readQuoted reads what follows an apostrophe
+func (p *parser) readQuoted(text *strings.Builder, inPlural bool) error {
+	if p.pos >= len(p.s) {
+		text.WriteByte('\'')
+		return nil
+	}
+
+	switch c := p.s[p.pos]; {
+	case c == '\'':
+		text.WriteByte('\'')
+		p.pos++
+		return nil
+	case c == '{' || c == '}' || c == '|' || (c == '#' && inPlural):
+		// quoted until the next single apostrophe, where doubled apostrophes are still apostrophes
+		for p.pos < len(p.s) {
+			if p.s[p.pos] == '\'' {
+				if p.pos+1 < len(p.s) && p.s[p.pos+1] == '\'' {
+					text.WriteByte('\'')
+					p.pos += 2
+					continue
+				}
+				p.pos++
+				return nil
+			}
+			text.WriteByte(p.s[p.pos])
+			p.pos++
+		}
+		// like ICU, quoted text without a closing apostrophe goes to the end
+		return nil
+	default:
+		text.WriteByte('\'')
+		return nil
+	}
+}
+
+func (p *parser) parseArgument(inPlural bool) (Node, error) {
+	start := p.pos
+	// the {
+	p.pos++
+
+	name := p.readIdentifier()
+	if len(name) == 0 {
+		return nil, p.error("Expected an argument name.")
+	}
+
+	p.skipSpace()
+	if p.consume('}') {
+		return Argument{Name: name}, nil
+	}
+	if !p.consume(',') {
+		return nil, p.error("Expected ',' or '}' after the argument name.")
+	}
+
+	p.skipSpace()
+	argType := p.readIdentifier()
+	p.skipSpace()
+
+	switch argType {
+	case "plural", "selectordinal":
+		if !p.consume(',') {
+			return nil, p.error(fmt.Sprintf("Expected ',' after '%v'.", argType))
+		}
+		plural := Plural{Argument: name, Ordinal: argType == "selectordinal"}
+
+		p.skipSpace()
+		if strings.HasPrefix(p.s[p.pos:], "offset:") {
+			p.pos += len("offset:")
+			p.skipSpace()
+			offset, err := strconv.Atoi(p.readIdentifier())
+			if err != nil {
+				return nil, p.error("Expected a number after 'offset:'.")
+			}
+			plural.Offset = offset
+		}
+
+		cases, err := p.parseCases(true)
+		if err != nil {
+			return nil, err
+		}
+		plural.Cases = cases
+		return plural, nil
+	case "select":
+		if !p.consume(',') {
+			return nil, p.error("Expected ',' after 'select'.")
+		}
+		cases, err := p.parseCases(inPlural)
+		if err != nil {
+			return nil, err
+		}
+		return Select{Argument: name, Cases: cases}, nil
+	case "":
+		return nil, p.error("Expected an argument type.")
+	}
+
+	argument := Argument{Name: name, Type: argType}
+	if p.consume('}') {
+		return argument, nil
+	}
+	if !p.consume(',') {
+		return nil, p.error(fmt.Sprintf("Expected ',' or '}' after '%v'.", argType))
+	}
+
+	// styles, like "integer" or "::currency/EUR", are kept as they are, up to the closing brace
+	styleStart := p.pos
+	depth := 0
+	for ; p.pos < len(p.s); p.pos++ {
+		switch p.s[p.pos] {
+		case '\'':
+			if end := strings.IndexByte(p.s[p.pos+1:], '\''); end >= 0 {
+				p.pos += end + 1
+			}
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				argument.Style = strings.TrimSpace(p.s[styleStart:p.pos])
+				p.pos++
+				return argument, nil
+			}
+			depth--
+		}
+	}
+	p.pos = start
+	return nil, p.error("Unterminated argument.")
+}
+
+func (p *parser) parseCases(inPlural bool) ([]Case, error) {
+	var cases []Case
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			break
+		}
+
+		selector := p.readIdentifier()
+		if len(selector) == 0 {
+			return nil, p.error("Expected a selector.")
+		}
+		for _, c := range cases {
+			if c.Selector == selector {
+				return nil, p.error(fmt.Sprint("Found a duplicate selector: ", selector))
+			}
+		}
+
+		p.skipSpace()
+		if !p.consume('{') {
+			return nil, p.error(fmt.Sprintf("Expected '{' after the selector '%v'.", selector))
+		}
+		message, err := p.parseMessage(inPlural)
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume('}') {
+			return nil, p.error(fmt.Sprintf("Unterminated case '%v'.", selector))
+		}
+
+		cases = append(cases, Case{selector, message})
+	}
+
+	if _, ok := findCase(cases, "other"); !ok {
+		return nil, p.error("Missing the 'other' case.")
+	}
+	return cases, nil
+}
+
+// readIdentifier reads names, types, selectors, and numbers, which all end at white space or syntax characters
+func (p *parser) readIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		r := rune(p.s[p.pos])
+		if unicode.IsSpace(r) || strings.ContainsRune("{},#'", r) {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) error(reason string) error {
+	return ParseError{p.s, p.pos, reason}
+}
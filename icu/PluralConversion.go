@@ -0,0 +1,262 @@
+package icu
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+// the argument of the plural, which po files have no place for, is kept in an extracted comment with this prefix,
+// so that converting a plural entry back gives the same message
+const pluralArgCommentPrefix = "icu-plural-arg: "
+
+const defaultPluralArg = "count"
+
+type ConversionError struct {
+	EntryKey        gettext.EntryKey
+	Reason          string
+	UnderlyingError error
+}
+
+func (e ConversionError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to convert entry: %v Entry: %+v Underlying error: %v", e.Reason, e.EntryKey, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to convert entry: %v Entry: %+v", e.Reason, e.EntryKey)
+}
+
+// PluralValues converts a message with a plural, like "{count, plural, one {# file} other {# files}}", to the msgstr[n] of a plural entry,
+// returning the argument of the plural. Each msgstr[n] gets the case of the plural category it's for, which, like with ICU, is the other case
+// for categories without one. Since values are no longer in a plural, # becomes the argument, like "{count} file",
+// and other parts of the message, like text around the plural, are moved into each value.
+// Messages without a plural, which translations to languages with only the other category often are, are every value.
+// Explicit cases, like =0, offsets, and messages with more than one plural have no gettext equivalent, so aren't supported.
+func PluralValues(m Message, plurals *gettext.PluralMapping) (string, []string, error) {
+	selectors := make([]string, plurals.NPlurals)
+	for i := range selectors {
+		selectors[i] = plurals.PluralType(i).Category()
+	}
+
+	arg, values, _, err := caseValues(m, selectors)
+	return arg, values, err
+}
+
+// caseValues returns whether the message had a plural, along with the values of each selector
+func caseValues(m Message, selectors []string) (string, []string, bool, error) {
+	pluralIndex := -1
+	for i, node := range m {
+		if plural, ok := node.(Plural); ok && !plural.Ordinal {
+			if pluralIndex >= 0 {
+				return "", nil, false, ConversionError{Reason: "Messages with more than one plural aren't supported."}
+			}
+			pluralIndex = i
+		}
+	}
+
+	values := make([]string, len(selectors))
+	if pluralIndex < 0 {
+		for i := range values {
+			values[i] = m.String()
+		}
+		return "", values, false, nil
+	}
+
+	plural := m[pluralIndex].(Plural)
+	if plural.Offset != 0 {
+		return "", nil, false, ConversionError{Reason: "Plurals with offsets aren't supported."}
+	}
+	for _, c := range plural.Cases {
+		if _, ok := gettext.PluralTypeFromCategory(c.Selector); !ok {
+			return "", nil, false, ConversionError{Reason: fmt.Sprint("Explicit cases aren't supported. Found: ", c.Selector)}
+		}
+	}
+
+	for i, selector := range selectors {
+		caseMessage, ok := plural.Case(selector)
+		if !ok {
+			caseMessage, _ = plural.Case("other")
+		}
+
+		var value Message
+		value = append(value, m[:pluralIndex]...)
+		value = append(value, replacePound(caseMessage, plural.Argument)...)
+		value = append(value, m[pluralIndex+1:]...)
+		values[i] = value.String()
+	}
+	return plural.Argument, values, true, nil
+}
+
+// replacePound replaces the # of a plural, including in selects in it, but not in other plurals, which have their own #
+func replacePound(m Message, arg string) Message {
+	result := make(Message, 0, len(m))
+	for _, node := range m {
+		switch n := node.(type) {
+		case Pound:
+			result = append(result, Argument{Name: arg})
+		case Select:
+			cases := make([]Case, len(n.Cases))
+			for i, c := range n.Cases {
+				cases[i] = Case{c.Selector, replacePound(c.Message, arg)}
+			}
+			result = append(result, Select{n.Argument, cases})
+		default:
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// PluralMessage is the reverse of PluralValues, creating a plural with a case per plural category of the mapping,
+// each with the msgstr[n] for the category, where the argument, like {count}, becomes #.
+func PluralMessage(arg string, values []string, plurals *gettext.PluralMapping) (Message, error) {
+	if len(values) != plurals.NPlurals {
+		return nil, ConversionError{Reason: fmt.Sprintf("Expected %v values but found %v.", plurals.NPlurals, len(values))}
+	}
+
+	plural := Plural{Argument: arg}
+	for _, pluralType := range plurals.Categories() {
+		m, err := Parse(plurals.Value(values, pluralType))
+		if err != nil {
+			return nil, err
+		}
+		plural.Cases = append(plural.Cases, Case{pluralType.Category(), insertPound(m, arg)})
+	}
+	return Message{plural}, nil
+}
+
+func insertPound(m Message, arg string) Message {
+	result := make(Message, 0, len(m))
+	for _, node := range m {
+		switch n := node.(type) {
+		case Argument:
+			if n.Name == arg && len(n.Type) == 0 {
+				result = append(result, Pound{})
+				continue
+			}
+			result = append(result, node)
+		case Select:
+			cases := make([]Case, len(n.Cases))
+			for i, c := range n.Cases {
+				cases[i] = Case{c.Selector, insertPound(c.Message, arg)}
+			}
+			result = append(result, Select{n.Argument, cases})
+		default:
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// ToPluralEntry converts an entry whose msgid and msgstr are ICU messages with a plural to a plural entry,
+// with the msgstr[n] that PluralValues creates.
+// The one and other cases of the msgid become the msgid and msgid_plural, and the argument of the plural is kept as a "#. icu-plural-arg: " comment.
+// Entries that are already plural, or whose msgid has no plural, are returned as they are.
+func ToPluralEntry(entry *gettext.Entry, plurals *gettext.PluralMapping) (gettext.Entry, error) {
+	if entry.IsPlural || entry.IsObsolete {
+		return *entry, nil
+	}
+
+	source, err := Parse(entry.Id)
+	if err != nil {
+		return gettext.Entry{}, ConversionError{entry.EntryKey, "Unable to parse the msgid.", err}
+	}
+	arg, sourceValues, isPlural, err := caseValues(source, []string{"one", "other"})
+	if err != nil {
+		return gettext.Entry{}, ConversionError{entry.EntryKey, "Unable to convert the msgid.", err}
+	}
+	if !isPlural {
+		return *entry, nil
+	}
+
+	values := make([]string, plurals.NPlurals)
+	if len(entry.Value) > 0 {
+		translation, err := Parse(entry.Value)
+		if err != nil {
+			return gettext.Entry{}, ConversionError{entry.EntryKey, "Unable to parse the msgstr.", err}
+		}
+		if _, values, err = PluralValues(translation, plurals); err != nil {
+			return gettext.Entry{}, ConversionError{entry.EntryKey, "Unable to convert the msgstr.", err}
+		}
+	}
+
+	key := entry.EntryKey
+	key.Id, key.IsPlural, key.PluralId = sourceValues[0], true, sourceValues[1]
+	header := entry.Header
+	header.ExtractedComments = append(slices.Clone(header.ExtractedComments), pluralArgCommentPrefix+arg)
+	return gettext.NewEntry(key, header, "", values), nil
+}
+
+// ToIcuEntry is the reverse of ToPluralEntry, converting a plural entry to one whose msgid and msgstr are ICU plural messages,
+// using the argument of the "#. icu-plural-arg: " comment, or "count" if there is none.
+// Entries that aren't plural are returned as they are.
+func ToIcuEntry(entry *gettext.Entry, plurals *gettext.PluralMapping) (gettext.Entry, error) {
+	if !entry.IsPlural || entry.IsObsolete {
+		return *entry, nil
+	}
+	if err := plurals.ValidatePluralCount(entry); err != nil {
+		return gettext.Entry{}, err
+	}
+
+	arg := defaultPluralArg
+	header := entry.Header
+	header.ExtractedComments = slices.DeleteFunc(slices.Clone(header.ExtractedComments), func(comment string) bool {
+		if strings.HasPrefix(comment, pluralArgCommentPrefix) {
+			arg = strings.TrimPrefix(comment, pluralArgCommentPrefix)
+			return true
+		}
+		return false
+	})
+
+	source, err := Parse(entry.Id)
+	if err != nil {
+		return gettext.Entry{}, ConversionError{entry.EntryKey, "Unable to parse the msgid.", err}
+	}
+	pluralSource, err := Parse(entry.PluralId)
+	if err != nil {
+		return gettext.Entry{}, ConversionError{entry.EntryKey, "Unable to parse the msgid_plural.", err}
+	}
+	id := Message{Plural{Argument: arg, Cases: []Case{{"one", insertPound(source, arg)}, {"other", insertPound(pluralSource, arg)}}}}
+
+	var value string
+	if entry.IsTranslated() {
+		m, err := PluralMessage(arg, entry.PluralValues, plurals)
+		if err != nil {
+			return gettext.Entry{}, ConversionError{entry.EntryKey, "Unable to convert the msgstr.", err}
+		}
+		value = m.String()
+	}
+
+	key := gettext.EntryKey{IsContextual: entry.IsContextual, Context: entry.Context, Id: id.String()}
+	return gettext.NewEntry(key, header, value, nil), nil
+}
+
+// ToPluralDocument converts each entry of the document with ToPluralEntry, using the document's plural mapping.
+func ToPluralDocument(d *gettext.Document) (gettext.Document, error) {
+	return convertDocument(d, ToPluralEntry)
+}
+
+// ToIcuDocument converts each entry of the document with ToIcuEntry, using the document's plural mapping.
+func ToIcuDocument(d *gettext.Document) (gettext.Document, error) {
+	return convertDocument(d, ToIcuEntry)
+}
+
+func convertDocument(d *gettext.Document, convert func(*gettext.Entry, *gettext.PluralMapping) (gettext.Entry, error)) (gettext.Document, error) {
+	plurals, err := d.Header.PluralMapping()
+	if err != nil {
+		return gettext.Document{}, err
+	}
+
+	entries := make([]gettext.Entry, len(d.Entries))
+	for i, entry := range d.Entries {
+		if i == 0 {
+			entries[i] = entry
+			continue
+		}
+		if entries[i], err = convert(&entry, &plurals); err != nil {
+			return gettext.Document{}, err
+		}
+	}
+	return gettext.CreateDocument(entries)
+}
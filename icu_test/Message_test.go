@@ -0,0 +1,83 @@
+package icu_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/icu"
+)
+
+func TestParse(t *testing.T) {
+	m, err := icu.Parse("{name} has {count, plural, offset:1 =0 {no files} one {# file '#'} other {{gender, select, female {her} other {their}} # files}}. It''s '{'done'}'")
+	if err != nil {
+		t.Fatal("Error parsing message: ", err)
+	}
+
+	expected := icu.Message{
+		icu.Argument{Name: "name"},
+		icu.Text(" has "),
+		icu.Plural{Argument: "count", Offset: 1, Cases: []icu.Case{
+			{Selector: "=0", Message: icu.Message{icu.Text("no files")}},
+			{Selector: "one", Message: icu.Message{icu.Pound{}, icu.Text(" file #")}},
+			{Selector: "other", Message: icu.Message{
+				icu.Select{Argument: "gender", Cases: []icu.Case{
+					{Selector: "female", Message: icu.Message{icu.Text("her")}},
+					{Selector: "other", Message: icu.Message{icu.Text("their")}},
+				}},
+				icu.Text(" "),
+				icu.Pound{},
+				icu.Text(" files"),
+			}},
+		}},
+		icu.Text(". It's {done}"),
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Expected:\n%#v\nGot:\n%#v", expected, m)
+	}
+}
+
+func TestParse_KeepsFormattedArguments(t *testing.T) {
+	m, err := icu.Parse("{price, number, ::currency/EUR} on {day, date, short} in {place, selectordinal, one {#st} other {#th}}")
+	if err != nil {
+		t.Fatal("Error parsing message: ", err)
+	}
+
+	if arg := m[0].(icu.Argument); arg.Type != "number" || arg.Style != "::currency/EUR" {
+		t.Errorf("Expected a number argument with a currency style, but got: %#v", arg)
+	}
+	if plural := m[4].(icu.Plural); !plural.Ordinal || len(plural.Cases) != 2 {
+		t.Errorf("Expected a selectordinal with two cases, but got: %#v", plural)
+	}
+}
+
+func TestMessageString_RoundTrips(t *testing.T) {
+	for _, s := range []string{
+		"Hello",
+		"{name} has {count, plural, offset:1 =0 {no files} one {# file '#'} other {{gender, select, female {her} other {their}} # files}}",
+		"It's '{'done'}' # {n, number, integer}",
+		"À l'heure {time}, l''{n, plural, one {l'''#' #''} other {''''}}",
+	} {
+		m, err := icu.Parse(s)
+		if err != nil {
+			t.Fatalf("Error parsing message %v: %v", s, err)
+		}
+		if actual := m.String(); actual != s {
+			t.Errorf("Expected %v but got %v", s, actual)
+		}
+	}
+}
+
+func TestParse_ReturnsError_WhenMalformed(t *testing.T) {
+	for _, s := range []string{
+		"{count, plural, one {# file}}",
+		"{count, plural, one {# file} other {# files}",
+		"{count, plural, one {a} one {b} other {c}}",
+		"oops}",
+		"{}",
+	} {
+		_, err := icu.Parse(s)
+		if _, ok := err.(icu.ParseError); !ok {
+			t.Errorf("Expected %T for %v but got %T: %+v", icu.ParseError{}, s, err, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package icu_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/icu"
+)
+
+const icuDocumentText = `msgid ""
+msgstr "Language: ru\n"
+
+#. shown in the toolbar
+msgctxt "toolbar"
+msgid "{count, plural, one {# file} other {# files}} left"
+msgstr "Осталось {count, plural, one {# файл} few {# файла} other {# файлов}}"
+
+msgid "Not a plural"
+msgstr "Не множественное"
+`
+
+const pluralDocumentText = `msgid ""
+msgstr "Language: ru\n"
+
+#. shown in the toolbar
+#. icu-plural-arg: count
+msgctxt "toolbar"
+msgid "{count} file left"
+msgid_plural "{count} files left"
+msgstr[0] "Осталось {count} файл"
+msgstr[1] "Осталось {count} файла"
+msgstr[2] "Осталось {count} файлов"
+
+msgid "Not a plural"
+msgstr "Не множественное"
+`
+
+func writeDocument(t *testing.T, d *gettext.Document) string {
+	var sb strings.Builder
+	if _, err := d.WriteTo(&sb); err != nil {
+		t.Fatal("Error writing document: ", err)
+	}
+	return sb.String()
+}
+
+func TestToPluralDocument(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(icuDocumentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	converted, err := icu.ToPluralDocument(&doc)
+	if err != nil {
+		t.Fatal("Error converting document: ", err)
+	}
+	if actual := writeDocument(t, &converted); actual != pluralDocumentText {
+		t.Errorf("Expected:\n%v\nGot:\n%v", pluralDocumentText, actual)
+	}
+}
+
+func TestToIcuDocument(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(pluralDocumentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	converted, err := icu.ToIcuDocument(&doc)
+	if err != nil {
+		t.Fatal("Error converting document: ", err)
+	}
+
+	entry, ok := converted.FindEntry(gettext.EntryKey{IsContextual: true, Context: "toolbar", Id: "{count, plural, one {# file left} other {# files left}}"})
+	if !ok {
+		t.Fatalf("Expected the converted entry, but got:\n%v", writeDocument(t, &converted))
+	}
+	expectedValue := "{count, plural, one {Осталось # файл} few {Осталось # файла} many {Осталось # файлов} other {Осталось # файлов}}"
+	if entry.Value != expectedValue {
+		t.Errorf("Expected %v but got %v", expectedValue, entry.Value)
+	}
+	if !slices.Equal(entry.Header.ExtractedComments, []string{"shown in the toolbar"}) {
+		t.Errorf("Expected the plural arg comment to be removed, but got: %v", entry.Header.ExtractedComments)
+	}
+}
+
+func TestPluralValues_ReturnsError_WhenExplicitCase(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(icuDocumentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	plurals, err := doc.Header.PluralMapping()
+	if err != nil {
+		t.Fatal("Error creating plural mapping: ", err)
+	}
+
+	m, err := icu.Parse("{count, plural, =0 {none} other {# files}}")
+	if err != nil {
+		t.Fatal("Error parsing message: ", err)
+	}
+	_, _, err = icu.PluralValues(m, &plurals)
+	if _, ok := err.(icu.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", icu.ConversionError{}, err, err)
+	}
+}
@@ -0,0 +1,23 @@
+package mt
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+)
+
+// Dictionary is a Translator that looks up translations for each target language, which, unlike translation services, works offline and always gives the same translations.
+// It's mainly for testing pipelines, before a real service is plugged in.
+type Dictionary map[language.Tag]map[string]string
+
+func (d Dictionary) Translate(ctx context.Context, texts []string, source language.Tag, target language.Tag) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	translations := make([]string, len(texts))
+	for i, text := range texts {
+		translations[i] = d[target][text]
+	}
+	return translations, nil
+}
@@ -0,0 +1,135 @@
+package mt
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+// machine translated entries are marked with an extracted comment, "#. MT", so they can be told apart from other fuzzy entries
+const machineTranslationComment = "MT"
+
+// Translator translates source strings, like a machine translation service does.
+type Translator interface {
+	// Translate translates the texts from the source language to the target language, returning a translation per text, in the same order.
+	// Texts that can't be translated have an empty translation.
+	Translate(ctx context.Context, texts []string, source language.Tag, target language.Tag) ([]string, error)
+}
+
+type Options struct {
+	// SourceLanguage is what msgids are translated from, defaulting to English.
+	SourceLanguage language.Tag
+}
+
+type TranslationError struct {
+	Reason          string
+	UnderlyingError error
+}
+
+func (e TranslationError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to translate: %v Underlying error: %v", e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to translate: %v", e.Reason)
+}
+
+// Fill fills the untranslated entries of the document with translations into the document's language, returning the number of entries filled.
+// Since machine translations need review, filled entries are marked fuzzy and with a "#. MT" comment.
+// The msgids of every entry are translated with a single call, where each string is only translated once, and the msgid_plural is translated
+// for each msgstr[n] that isn't for the one plural category.
+// Entries the translator has no translation for are left untranslated, and aren't counted.
+func Fill(ctx context.Context, d *gettext.Document, translator Translator, options Options) (int, error) {
+	sourceLanguage := options.SourceLanguage
+	if sourceLanguage == language.Und {
+		sourceLanguage = language.English
+	}
+	plurals, err := d.Header.PluralMapping()
+	if err != nil {
+		return 0, err
+	}
+
+	var keys []gettext.EntryKey
+	var texts []string
+	textIndexes := make(map[string]int)
+	addText := func(text string) {
+		if _, ok := textIndexes[text]; !ok {
+			textIndexes[text] = len(texts)
+			texts = append(texts, text)
+		}
+	}
+	for i, entry := range d.Entries {
+		if i == 0 || entry.IsObsolete {
+			continue
+		}
+		if !entry.IsPlural {
+			if len(entry.Value) > 0 {
+				continue
+			}
+			addText(entry.Id)
+		} else {
+			if slices.ContainsFunc(entry.PluralValues, func(v string) bool { return len(v) > 0 }) {
+				continue
+			}
+			if err := plurals.ValidatePluralCount(&entry); err != nil {
+				return 0, err
+			}
+			for i := range entry.PluralValues {
+				addText(entry.PluralSource(plurals.PluralType(i)))
+			}
+		}
+		keys = append(keys, entry.EntryKey)
+	}
+	if len(texts) == 0 {
+		return 0, nil
+	}
+
+	translations, err := translator.Translate(ctx, texts, sourceLanguage, d.Header.Tag)
+	if err != nil {
+		return 0, TranslationError{fmt.Sprintf("Unable to translate %v strings.", len(texts)), err}
+	}
+	if len(translations) != len(texts) {
+		return 0, TranslationError{Reason: fmt.Sprintf("Expected %v translations but got %v.", len(texts), len(translations))}
+	}
+
+	filled := 0
+	for _, key := range keys {
+		entry, _ := d.FindEntry(key)
+
+		var update gettext.EntryUpdate
+		if !key.IsPlural {
+			translation := translations[textIndexes[key.Id]]
+			if len(translation) == 0 {
+				continue
+			}
+			update.Value = &translation
+		} else {
+			values := make([]string, plurals.NPlurals)
+			for i := range values {
+				values[i] = translations[textIndexes[key.PluralSource(plurals.PluralType(i))]]
+			}
+			if !slices.ContainsFunc(values, func(v string) bool { return len(v) > 0 }) {
+				continue
+			}
+			update.PluralValues = values
+		}
+
+		header := entry.Header
+		header.SetFuzzy(true)
+		if !slices.Contains(header.ExtractedComments, machineTranslationComment) {
+			header.ExtractedComments = append(slices.Clone(header.ExtractedComments), machineTranslationComment)
+		}
+		update.Header = &header
+
+		changed, err := d.UpdateEntry(key, update)
+		if err != nil {
+			return filled, err
+		}
+		if changed {
+			filled++
+		}
+	}
+	return filled, nil
+}
@@ -0,0 +1,147 @@
+package mt_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/mt"
+	"golang.org/x/text/language"
+)
+
+const documentText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. The open button
+#: main.go:1
+msgctxt "toolbar"
+msgid "Open"
+msgstr ""
+
+msgid "Close"
+msgstr "Закрыть"
+
+msgid "Untranslatable"
+msgstr ""
+
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+msgstr[2] ""
+
+#~ msgid "Old"
+#~ msgstr ""
+`
+
+var dictionary = mt.Dictionary{
+	language.Russian: {
+		"Open":     "Открыть",
+		"Close":    "Закрыть окно",
+		"Old":      "Старый",
+		"%d file":  "%d файл",
+		"%d files": "%d файлов",
+	},
+}
+
+func TestFill(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	filled, err := mt.Fill(context.Background(), &doc, dictionary, mt.Options{})
+	if err != nil {
+		t.Fatal("Error filling document: ", err)
+	}
+	if filled != 2 {
+		t.Errorf("Expected 2 entries filled but got %v", filled)
+	}
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal("Error writing document: ", err)
+	}
+	expected := `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. The open button
+#. MT
+#: main.go:1
+#, fuzzy
+msgctxt "toolbar"
+msgid "Open"
+msgstr "Открыть"
+
+msgid "Close"
+msgstr "Закрыть"
+
+msgid "Untranslatable"
+msgstr ""
+
+#. MT
+#, fuzzy, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файлов"
+msgstr[2] "%d файлов"
+
+#~ msgid "Old"
+#~ msgstr ""
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buf.String())
+	}
+}
+
+// countingTranslator records what it was asked to translate
+type countingTranslator struct {
+	texts        []string
+	translations []string
+	err          error
+}
+
+func (c *countingTranslator) Translate(ctx context.Context, texts []string, source language.Tag, target language.Tag) ([]string, error) {
+	c.texts = append(c.texts, texts...)
+	return c.translations, c.err
+}
+
+func TestFillTranslatesEachStringOnce(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	translator := &countingTranslator{translations: []string{"", "", "", ""}}
+
+	if _, err := mt.Fill(context.Background(), &doc, translator, mt.Options{}); err != nil {
+		t.Fatal("Error filling document: ", err)
+	}
+	if len(translator.texts) != 4 {
+		t.Errorf("Expected 4 texts but got %v: %v", len(translator.texts), translator.texts)
+	}
+}
+
+func TestFillTranslatorErrors(t *testing.T) {
+	translators := []mt.Translator{
+		&countingTranslator{err: errors.New("service unavailable")},
+		&countingTranslator{translations: []string{"Открыть"}},
+	}
+	for _, translator := range translators {
+		doc, err := gettext.ParseDocumentString(documentText)
+		if err != nil {
+			t.Fatal("Error parsing document: ", err)
+		}
+		_, err = mt.Fill(context.Background(), &doc, translator, mt.Options{})
+		if _, ok := err.(mt.TranslationError); !ok {
+			t.Errorf("Expected %T but got %T: %+v", mt.TranslationError{}, err, err)
+		}
+	}
+}
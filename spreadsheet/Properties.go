@@ -0,0 +1,129 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+type PropertiesOptions struct {
+	// EscapeUnicode writes non-ASCII characters as \uXXXX escapes, which Properties.load(InputStream) needs,
+	// since it reads ISO-8859-1. ResourceBundles read UTF-8 since Java 9.
+	EscapeUnicode bool
+
+	// IncludeFuzzy also writes fuzzy entries, which otherwise fall back to the default bundle.
+	IncludeFuzzy bool
+}
+
+// WriteProperties writes the translations of the document as a Java .properties file, with the same mapping as Write,
+// where the key is the msgctxt, or the msgid for entries without one, and plural entries have a key per plural category of the document,
+// like "files.one" and "files.other". Extracted comments are written as comments before each key.
+// Untranslated and obsolete entries are skipped, so that ResourceBundles fall back to a parent bundle.
+func WriteProperties(w io.Writer, d *gettext.Document, options PropertiesOptions) error {
+	plurals := sync.OnceValues(d.Header.PluralMapping)
+	var sb strings.Builder
+
+	for i, entry := range d.Entries {
+		if i == 0 || entry.IsObsolete || (entry.Header.IsFuzzy() && !options.IncludeFuzzy) {
+			continue
+		}
+
+		key := entry.Id
+		if entry.IsContextual {
+			key = entry.Context
+		}
+
+		var properties [][2]string
+		if !entry.IsPlural {
+			if len(entry.Value) > 0 {
+				properties = append(properties, [2]string{key, entry.Value})
+			}
+		} else {
+			plurals, err := plurals()
+			if err != nil {
+				return err
+			}
+			if err := plurals.ValidatePluralCount(&entry); err != nil {
+				return err
+			}
+
+			for _, pluralType := range plurals.Categories() {
+				if value := plurals.Value(entry.PluralValues, pluralType); len(value) > 0 {
+					properties = append(properties, [2]string{fmt.Sprint(key, ".", pluralType.Category()), value})
+				}
+			}
+		}
+		if len(properties) == 0 {
+			continue
+		}
+
+		for _, comment := range entry.Header.ExtractedComments {
+			for _, line := range strings.Split(comment, "\n") {
+				sb.WriteString(escapeUnicode(strings.TrimSpace("# "+line), options.EscapeUnicode))
+				sb.WriteString("\n")
+			}
+		}
+		for _, property := range properties {
+			sb.WriteString(escapeProperty(property[0], true, options.EscapeUnicode))
+			sb.WriteString("=")
+			sb.WriteString(escapeProperty(property[1], false, options.EscapeUnicode))
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// escapeProperty escapes the way java.util.Properties.store does
+func escapeProperty(s string, isKey bool, escapeNonAscii bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case ' ':
+			// spaces only need escaping when they would otherwise be trimmed or end the key
+			if isKey || i == 0 {
+				sb.WriteRune('\\')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteString(escapeUnicode(string(r), escapeNonAscii))
+		}
+	}
+	return sb.String()
+}
+
+func escapeUnicode(s string, escapeNonAscii bool) string {
+	if !escapeNonAscii {
+		return s
+	}
+
+	var sb strings.Builder
+	for _, r := range s {
+		if r < 0x80 {
+			sb.WriteRune(r)
+			continue
+		}
+		for _, unit := range utf16.Encode([]rune{r}) {
+			fmt.Fprintf(&sb, `\u%04X`, unit)
+		}
+	}
+	return sb.String()
+}
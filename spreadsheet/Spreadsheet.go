@@ -0,0 +1,226 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+// the columns, where plural entries have a translation column per msgstr[n] of the document, such as "Translation (one)"
+const (
+	contextColumn = "Context"
+	// hasContextColumn tells an empty msgctxt apart from none at all, which the context column alone can't
+	hasContextColumn        = "Has context"
+	sourceColumn            = "Source"
+	pluralSourceColumn      = "Source (plural)"
+	translationColumn       = "Translation"
+	flagsColumn             = "Flags"
+	commentsColumn          = "Comments"
+	extractedCommentsColumn = "Extracted comments"
+	referencesColumn        = "References"
+)
+
+type Options struct {
+	// Comma is the delimiter between cells, which is ',' if not set. Use '\t' for TSV.
+	Comma rune
+}
+
+type ConversionError struct {
+	Row             int
+	Reason          string
+	UnderlyingError error
+}
+
+func (e ConversionError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to convert row %v: %v Underlying error: %v", e.Row, e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to convert row %v: %v", e.Row, e.Reason)
+}
+
+// pluralColumns are named after the plural type of each msgstr[n], or the n of msgstr[n] for ones that share a plural type,
+// which Plural-Forms with more msgstr[n] than integers reach can have
+func pluralColumns(plurals *gettext.PluralMapping) []string {
+	result := make([]string, plurals.NPlurals)
+	found := make(map[gettext.PluralType]bool)
+	for i := range result {
+		pluralType := plurals.PluralType(i)
+		if found[pluralType] {
+			result[i] = fmt.Sprintf("%v [%v]", translationColumn, i)
+			continue
+		}
+		found[pluralType] = true
+		result[i] = fmt.Sprintf("%v (%v)", translationColumn, pluralType.Category())
+	}
+	return result
+}
+
+func columns(plurals *gettext.PluralMapping) []string {
+	result := []string{contextColumn, hasContextColumn, sourceColumn, pluralSourceColumn, translationColumn}
+	result = append(result, pluralColumns(plurals)...)
+	return append(result, flagsColumn, commentsColumn, extractedCommentsColumn, referencesColumn)
+}
+
+// Write writes the document as a spreadsheet with a header row, followed by a row per entry.
+// Plural entries have their translations in a column per msgstr[n], named after the plural category the document's Plural-Forms pick it for,
+// instead of the translation column.
+// Multiple flags are separated with commas, and multiple comments and references with new lines.
+// The header and obsolete entries are skipped.
+func Write(w io.Writer, d *gettext.Document, options Options) error {
+	plurals, err := d.Header.PluralMapping()
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if options.Comma != 0 {
+		cw.Comma = options.Comma
+	}
+	if err := cw.Write(columns(&plurals)); err != nil {
+		return err
+	}
+
+	for i, entry := range d.Entries {
+		if i == 0 || entry.IsObsolete {
+			continue
+		}
+		if err := plurals.ValidatePluralCount(&entry); err != nil {
+			return err
+		}
+
+		row := []string{entry.Context, strconv.FormatBool(entry.IsContextual), entry.Id, entry.PluralId, entry.Value}
+		if entry.IsPlural {
+			row = append(row, entry.PluralValues...)
+		} else {
+			row = append(row, make([]string, plurals.NPlurals)...)
+		}
+		row = append(row,
+			strings.Join(entry.Header.Flags, ", "),
+			strings.Join(entry.Header.TranslatorComments, "\n"),
+			strings.Join(entry.Header.ExtractedComments, "\n"),
+			strings.Join(entry.Header.References, "\n"))
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Apply applies the translations, flags, and comments of a spreadsheet written by Write back onto the document, returning the number of entries that changed.
+// Columns are found by the header row, so columns may be reordered or removed, in which case what they have is left as it is.
+// Without the has context column, only entries with a non-empty context cell are looked up by msgctxt.
+// Extracted comments and references come from the source code, so edits to them aren't applied.
+// Rows that match their entry leave it as it is, and aren't counted.
+func Apply(r io.Reader, d *gettext.Document, options Options) (int, error) {
+	plurals, err := d.Header.PluralMapping()
+	if err != nil {
+		return 0, err
+	}
+	pluralColumns := pluralColumns(&plurals)
+
+	cr := csv.NewReader(r)
+	if options.Comma != 0 {
+		cr.Comma = options.Comma
+	}
+	// spreadsheet apps sometimes leave off trailing empty cells
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return 0, ConversionError{Reason: "Unable to read the spreadsheet.", UnderlyingError: err}
+	}
+	if len(rows) == 0 {
+		return 0, ConversionError{Reason: "Missing the header row."}
+	}
+
+	columnIndexes := make(map[string]int)
+	for i, column := range rows[0] {
+		columnIndexes[strings.TrimSpace(column)] = i
+	}
+	if _, ok := columnIndexes[sourceColumn]; !ok {
+		return 0, ConversionError{Row: 1, Reason: fmt.Sprintf("Missing the '%v' column.", sourceColumn)}
+	}
+	cell := func(row []string, column string) (string, bool) {
+		i, ok := columnIndexes[column]
+		if !ok || i >= len(row) {
+			return "", false
+		}
+		return row[i], true
+	}
+
+	changes := 0
+	for i, row := range rows[1:] {
+		rowNumber := i + 2
+
+		context, _ := cell(row, contextColumn)
+		id, _ := cell(row, sourceColumn)
+		key := gettext.EntryKey{IsContextual: len(context) > 0, Context: context, Id: id}
+		if hasContext, ok := cell(row, hasContextColumn); ok && len(strings.TrimSpace(hasContext)) > 0 {
+			if key.IsContextual, err = strconv.ParseBool(strings.TrimSpace(hasContext)); err != nil {
+				return changes, ConversionError{Row: rowNumber, Reason: fmt.Sprintf("Invalid '%v' cell.", hasContextColumn), UnderlyingError: err}
+			}
+		}
+		entry, ok := d.FindEntry(key)
+		if !ok {
+			return changes, ConversionError{Row: rowNumber, Reason: fmt.Sprintf("No entry found for: %+v", key)}
+		}
+
+		var update gettext.EntryUpdate
+		if !entry.IsPlural {
+			if value, ok := cell(row, translationColumn); ok {
+				update.Value = &value
+			}
+		} else {
+			pluralValues := slices.Clone(entry.PluralValues)
+			for i, column := range pluralColumns {
+				if value, ok := cell(row, column); ok && i < len(pluralValues) {
+					pluralValues[i] = value
+				}
+			}
+			update.PluralValues = pluralValues
+		}
+
+		header := entry.Header
+		if flags, ok := cell(row, flagsColumn); ok {
+			header.Flags = splitCell(flags, ",")
+		}
+		if comments, ok := cell(row, commentsColumn); ok {
+			header.TranslatorComments = splitLines(comments)
+		}
+		update.Header = &header
+
+		changed, err := d.UpdateEntry(key, update)
+		if err != nil {
+			return changes, ConversionError{Row: rowNumber, Reason: "Unable to update the entry.", UnderlyingError: err}
+		}
+		if changed {
+			changes++
+		}
+	}
+	return changes, nil
+}
+
+// splitLines keeps empty and indented comments as they are, unlike splitCell
+func splitLines(cell string) []string {
+	if len(cell) == 0 {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(cell, "\r\n", "\n"), "\n")
+}
+
+func splitCell(cell string, separator string) []string {
+	var result []string
+	for _, s := range strings.Split(cell, separator) {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,225 @@
+package spreadsheet_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/spreadsheet"
+)
+
+const documentText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Shown as "Files, Folders" in the menu
+#: main.go:1
+msgctxt "app.name"
+msgid "Files"
+msgstr "Файлы"
+
+#, fuzzy, c-format
+msgid "Save %s"
+msgstr "Сохр %s"
+
+msgctxt "path"
+msgid "C:\\Files"
+msgstr "C:\\Файлы"
+
+msgctxt "files"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] ""
+
+#~ msgid "Old"
+#~ msgstr "Старый"
+`
+
+const expectedCsv = `Context,Has context,Source,Source (plural),Translation,Translation (one),Translation (few),Translation (many),Flags,Comments,Extracted comments,References
+app.name,true,Files,,Файлы,,,,,,"Shown as ""Files, Folders"" in the menu",main.go:1
+,false,Save %s,,Сохр %s,,,,"fuzzy, c-format",,,
+path,true,C:\Files,,C:\Файлы,,,,,,,
+files,true,%d file,%d files,,%d файл,%d файла,,,,,
+`
+
+func TestWrite(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := spreadsheet.Write(&buf, &doc, spreadsheet.Options{}); err != nil {
+		t.Fatal("Error writing csv: ", err)
+	}
+	if buf.String() != expectedCsv {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedCsv, buf.String())
+	}
+}
+
+func TestApply(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := spreadsheet.Write(&buf, &doc, spreadsheet.Options{Comma: '\t'}); err != nil {
+		t.Fatal("Error writing tsv: ", err)
+	}
+	edited := strings.Replace(buf.String(), "Сохр %s\t\t\t\tfuzzy, c-format\t", "Сохранить %s\t\t\t\tc-format\t\"читал\nвычитал\"", 1)
+	edited = strings.Replace(edited, "%d файла\t\t", "%d файла\t%d файлов\t", 1)
+
+	changes, err := spreadsheet.Apply(strings.NewReader(edited), &doc, spreadsheet.Options{Comma: '\t'})
+	if err != nil {
+		t.Fatal("Error applying tsv: ", err)
+	}
+	if changes != 2 {
+		t.Errorf("Expected 2 changes but got %v", changes)
+	}
+
+	expected := strings.Replace(documentText, `#, fuzzy, c-format
+msgid "Save %s"
+msgstr "Сохр %s"`, `# читал
+# вычитал
+#, c-format
+msgid "Save %s"
+msgstr "Сохранить %s"`, 1)
+	expected = strings.Replace(expected, `msgstr[2] ""`, `msgstr[2] "%d файлов"`, 1)
+
+	var sb strings.Builder
+	if _, err := doc.WriteTo(&sb); err != nil {
+		t.Fatal("Error writing document: ", err)
+	}
+	if sb.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, sb.String())
+	}
+}
+
+func TestApply_FindsEntriesWithEmptyContext(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"
+
+msgctxt ""
+msgid "Files"
+msgstr ""
+
+msgid "Files"
+msgstr ""
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := spreadsheet.Write(&buf, &doc, spreadsheet.Options{}); err != nil {
+		t.Fatal("Error writing csv: ", err)
+	}
+	edited := strings.Replace(buf.String(), ",true,Files,,,", ",true,Files,,Файлы,", 1)
+
+	changes, err := spreadsheet.Apply(strings.NewReader(edited), &doc, spreadsheet.Options{})
+	if err != nil {
+		t.Fatal("Error applying csv: ", err)
+	}
+	if changes != 1 {
+		t.Errorf("Expected 1 change but got %v", changes)
+	}
+
+	if entry, _ := doc.FindEntry(gettext.EntryKey{IsContextual: true, Id: "Files"}); entry.Value != "Файлы" {
+		t.Errorf("Expected the entry with the empty msgctxt to be translated, but got %v", entry.Value)
+	}
+	if entry, _ := doc.FindEntry(gettext.EntryKey{Id: "Files"}); len(entry.Value) > 0 {
+		t.Errorf("Expected the entry without a msgctxt to be untranslated, but got %v", entry.Value)
+	}
+}
+
+func TestApply_KeepsColumnsThatAreMissing(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	changes, err := spreadsheet.Apply(strings.NewReader("Source,Context\nFiles,app.name\n"), &doc, spreadsheet.Options{})
+	if err != nil {
+		t.Fatal("Error applying csv: ", err)
+	}
+	if changes != 0 {
+		t.Errorf("Expected no changes but got %v", changes)
+	}
+}
+
+func TestApply_ReturnsError_WhenEntryNotFound(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	_, err = spreadsheet.Apply(strings.NewReader("Source,Translation\nMissing,Нет\n"), &doc, spreadsheet.Options{})
+	if _, ok := err.(spreadsheet.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", spreadsheet.ConversionError{}, err, err)
+	}
+}
+
+func TestWrite_NamesColumnsByIndex_WhenPluralFormsHaveUnreachedForms(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr ""
+"Language: en\n"
+"Plural-Forms: nplurals=3; plural=(n != 1);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d file"
+msgstr[1] "%d files"
+msgstr[2] ""
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := spreadsheet.Write(&buf, &doc, spreadsheet.Options{}); err != nil {
+		t.Fatal("Error writing csv: ", err)
+	}
+	// no integer picks msgstr[2], so it's left as other, like msgstr[1]
+	if s := "Translation,Translation (one),Translation (other),Translation [2],"; !strings.Contains(buf.String(), s) {
+		t.Errorf("Expected %v in:\n%v", s, buf.String())
+	}
+}
+
+func TestWriteProperties(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := spreadsheet.WriteProperties(&buf, &doc, spreadsheet.PropertiesOptions{IncludeFuzzy: true}); err != nil {
+		t.Fatal("Error writing properties: ", err)
+	}
+
+	expected := `# Shown as "Files, Folders" in the menu
+app.name=Файлы
+Save\ %s=Сохр %s
+path=C\:\\Файлы
+files.one=%d файл
+files.few=%d файла
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := spreadsheet.WriteProperties(&buf, &doc, spreadsheet.PropertiesOptions{EscapeUnicode: true}); err != nil {
+		t.Fatal("Error writing properties: ", err)
+	}
+	if s := `app.name=\u0424\u0430\u0439\u043B\u044B`; !strings.Contains(buf.String(), s) {
+		t.Errorf("Expected %v in:\n%v", s, buf.String())
+	}
+	if strings.Contains(buf.String(), "Save") {
+		t.Errorf("Expected the fuzzy entry to be skipped:\n%v", buf.String())
+	}
+}
@@ -0,0 +1,47 @@
+package tm
+
+import "unicode/utf8"
+
+// Similarity is how alike the strings are, from 0 to 1, based on the edit distance between them,
+// where 1 is the same string and 0 has no characters in common.
+func Similarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// maxSimilarity is the most the strings could be alike, based on their lengths alone, which is a lot cheaper than Similarity
+func maxSimilarity(a string, b string) float64 {
+	la, lb := utf8.RuneCountInString(a), utf8.RuneCountInString(b)
+	longest := max(la, lb)
+	if longest == 0 {
+		return 1
+	}
+	return float64(min(la, lb)) / float64(longest)
+}
+
+// editDistance is the Levenshtein distance, which only needs the previous row of the usual table
+func editDistance(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
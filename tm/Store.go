@@ -0,0 +1,226 @@
+package tm
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/tmx"
+	"golang.org/x/text/language"
+)
+
+// near matches are marked with an extracted comment with this prefix, like "#. tm-similarity: 87%", so translators know how much to check
+const similarityCommentPrefix = "tm-similarity: "
+
+const defaultMinSimilarity = 0.75
+
+type Options struct {
+	// MinSimilarity is how alike, from 0 to 1, source strings need to be for a near match. 0.75 is used if not set.
+	MinSimilarity float64
+}
+
+type StoreError struct {
+	Path            string
+	Reason          string
+	UnderlyingError error
+}
+
+func (e StoreError) Error() string {
+	return fmt.Sprintf("Failed to %v: %v Underlying error: %v", e.Reason, e.Path, e.UnderlyingError)
+}
+
+// Store is a translation memory kept on disk as a TMX file, so that it can also be shared with CAT tools.
+type Store struct {
+	Path   string
+	Memory tmx.Memory
+}
+
+// Open opens the store at the path, which is empty if there's no file yet.
+func Open(path string) (*Store, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Store{Path: path}, nil
+	}
+	if err != nil {
+		return nil, StoreError{path, "open translation memory", err}
+	}
+	defer f.Close()
+
+	m, err := tmx.Read(f)
+	if err != nil {
+		return nil, StoreError{path, "read translation memory", err}
+	}
+	return &Store{Path: path, Memory: m}, nil
+}
+
+// Add adds the translations of the documents, which replace earlier translations of the same strings into the same language.
+func (s *Store) Add(documents ...*gettext.Document) error {
+	for _, d := range documents {
+		if err := s.Memory.AddDocument(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save writes the store to its path, replacing the file only once it's completely written.
+func (s *Store) Save() error {
+	temp := s.Path + ".tmp"
+	f, err := os.Create(temp)
+	if err != nil {
+		return StoreError{temp, "create translation memory", err}
+	}
+
+	if err := tmx.Write(f, &s.Memory); err != nil {
+		f.Close()
+		os.Remove(temp)
+		return StoreError{temp, "write translation memory", err}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(temp)
+		return StoreError{temp, "write translation memory", err}
+	}
+	if err := os.Rename(temp, s.Path); err != nil {
+		return StoreError{s.Path, "replace translation memory", err}
+	}
+	return nil
+}
+
+type Result struct {
+	// Exact is the number of entries filled with translations of the same string in the same context.
+	Exact int
+	// Near is the number of entries filled with translations of similar strings, or the same string in another context, which are marked fuzzy.
+	Near int
+}
+
+type match struct {
+	translation string
+	similarity  float64
+	exact       bool
+}
+
+// PreTranslate fills the untranslated entries of the document with the translations into the document's language.
+// Exact matches are filled as they are, and near matches are marked fuzzy with a "#. tm-similarity: 87%" comment.
+// For plural entries, each msgstr[n] is matched on its own, with the plural category it's for,
+// and the entry is only an exact match if every msgstr[n] is.
+func (s *Store) PreTranslate(d *gettext.Document, options Options) (Result, error) {
+	minSimilarity := options.MinSimilarity
+	if minSimilarity == 0 {
+		minSimilarity = defaultMinSimilarity
+	}
+	plurals, err := d.Header.PluralMapping()
+	if err != nil {
+		return Result{}, err
+	}
+	tag := d.Header.Tag
+
+	var result Result
+	for i := 1; i < len(d.Entries); i++ {
+		entry := d.Entries[i]
+		if entry.IsObsolete {
+			continue
+		}
+
+		var update gettext.EntryUpdate
+		var similarity float64
+		exact := true
+		if !entry.IsPlural {
+			if len(entry.Value) > 0 {
+				continue
+			}
+			m, ok := s.bestMatch(&entry.EntryKey, entry.Id, "", tag, minSimilarity)
+			if !ok {
+				continue
+			}
+			update.Value = &m.translation
+			similarity, exact = m.similarity, m.exact
+		} else {
+			if slices.ContainsFunc(entry.PluralValues, func(v string) bool { return len(v) > 0 }) {
+				continue
+			}
+			if err := plurals.ValidatePluralCount(&entry); err != nil {
+				return result, err
+			}
+
+			values := make([]string, len(entry.PluralValues))
+			similarity = 1
+			found := false
+			for i := range values {
+				pluralType := plurals.PluralType(i)
+				m, ok := s.bestMatch(&entry.EntryKey, entry.PluralSource(pluralType), pluralType.Category(), tag, minSimilarity)
+				if !ok {
+					exact = false
+					continue
+				}
+				values[i], found = m.translation, true
+				similarity, exact = min(similarity, m.similarity), exact && m.exact
+			}
+			if !found {
+				continue
+			}
+			update.PluralValues = values
+		}
+
+		if !exact {
+			header := entry.Header
+			header.SetFuzzy(true)
+			header.ExtractedComments = slices.DeleteFunc(slices.Clone(header.ExtractedComments), func(comment string) bool {
+				return strings.HasPrefix(comment, similarityCommentPrefix)
+			})
+			header.ExtractedComments = append(header.ExtractedComments, fmt.Sprintf("%v%v%%", similarityCommentPrefix, int(similarity*100)))
+			update.Header = &header
+		}
+
+		changed, err := d.UpdateEntry(entry.EntryKey, update)
+		if err != nil {
+			return result, err
+		}
+		if !changed {
+			continue
+		}
+		if exact {
+			result.Exact++
+		} else {
+			result.Near++
+		}
+	}
+	return result, nil
+}
+
+// bestMatch finds the translation of the same string in the same context, or else the most similar string in any context, preferring earlier units on ties.
+// Only translations into the language itself are exact, since ones into another variant of it, like pt-BR for pt-PT, usually need some changes.
+func (s *Store) bestMatch(key *gettext.EntryKey, source string, pluralCategory string, tag language.Tag, minSimilarity float64) (match, bool) {
+	if unit, ok := s.Memory.Find(key.IsContextual, key.Context, source, pluralCategory); ok {
+		if translation, ok := unit.Translations[tag]; ok {
+			return match{translation, 1, true}, true
+		}
+		if translation, ok := unit.Translation(tag); ok {
+			return match{translation, 1, false}, true
+		}
+	}
+
+	var best match
+	found := false
+	for i := range s.Memory.Units {
+		unit := &s.Memory.Units[i]
+		if unit.PluralCategory != pluralCategory {
+			continue
+		}
+		// most units aren't anywhere near similar, which their lengths alone are usually enough to tell
+		if upperBound := maxSimilarity(unit.Source, source); upperBound < minSimilarity || (found && upperBound <= best.similarity) {
+			continue
+		}
+		translation, ok := unit.Translation(tag)
+		if !ok {
+			continue
+		}
+		if similarity := Similarity(unit.Source, source); similarity >= minSimilarity && (!found || similarity > best.similarity) {
+			best, found = match{translation, similarity, false}, true
+		}
+	}
+	return best, found
+}
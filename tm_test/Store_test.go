@@ -0,0 +1,235 @@
+package tm_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/tm"
+)
+
+func createStore(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "memory.tmx")
+	store, err := tm.Open(path)
+	if err != nil {
+		t.Fatal("Error opening store: ", err)
+	}
+
+	first, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Открыть"
+
+msgid "Delete the selected files"
+msgstr "Удалить выбранные файлы"
+
+#, fuzzy
+msgid "Cancel"
+msgstr "Отмена"
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	second, err := gettext.ParseDocumentString(`msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	if err := store.Add(&first, &second); err != nil {
+		t.Fatal("Error adding documents: ", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal("Error saving store: ", err)
+	}
+	return path
+}
+
+func TestPreTranslate(t *testing.T) {
+	store, err := tm.Open(createStore(t))
+	if err != nil {
+		t.Fatal("Error reopening store: ", err)
+	}
+
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"
+
+msgctxt "menu"
+msgid "Open"
+msgstr ""
+
+msgctxt "toolbar"
+msgid "Open"
+msgstr ""
+
+#, c-format
+msgid "Delete the selected file"
+msgstr ""
+
+msgid "Cancel"
+msgstr ""
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+msgstr[2] ""
+
+msgid "Delete the selected files"
+msgstr "Удалить файлы"
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	result, err := store.PreTranslate(&doc, tm.Options{})
+	if err != nil {
+		t.Fatal("Error pre-translating: ", err)
+	}
+	if result != (tm.Result{Exact: 2, Near: 2}) {
+		t.Errorf("Expected 2 exact and 2 near matches but got %+v", result)
+	}
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal("Error writing document: ", err)
+	}
+	expected := `msgid ""
+msgstr "Language: ru\n"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Открыть"
+
+#. tm-similarity: 100%
+#, fuzzy
+msgctxt "toolbar"
+msgid "Open"
+msgstr "Открыть"
+
+#. tm-similarity: 96%
+#, fuzzy, c-format
+msgid "Delete the selected file"
+msgstr "Удалить выбранные файлы"
+
+msgid "Cancel"
+msgstr ""
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+
+msgid "Delete the selected files"
+msgstr "Удалить файлы"
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buf.String())
+	}
+}
+
+func TestPreTranslate_MarksOtherVariantsFuzzy(t *testing.T) {
+	store, err := tm.Open(filepath.Join(t.TempDir(), "memory.tmx"))
+	if err != nil {
+		t.Fatal("Error opening store: ", err)
+	}
+	brazilian, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: pt_BR\n"
+
+msgid "Save the file"
+msgstr "Salvar o arquivo"
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	if err := store.Add(&brazilian); err != nil {
+		t.Fatal("Error adding documents: ", err)
+	}
+
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: pt_PT\n"
+
+msgid "Save the file"
+msgstr ""
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	result, err := store.PreTranslate(&doc, tm.Options{})
+	if err != nil {
+		t.Fatal("Error pre-translating: ", err)
+	}
+	if result != (tm.Result{Near: 1}) {
+		t.Errorf("Expected a near match but got %+v", result)
+	}
+	if entry := doc.Entries[1]; !entry.Header.IsFuzzy() || entry.Value != "Salvar o arquivo" {
+		t.Errorf("Expected the pt-BR translation marked fuzzy, but got %+v %v", entry.Header, entry.Value)
+	}
+}
+
+func TestPreTranslateMinSimilarity(t *testing.T) {
+	store, err := tm.Open(createStore(t))
+	if err != nil {
+		t.Fatal("Error reopening store: ", err)
+	}
+
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"
+
+msgid "Delete the selected file"
+msgstr ""
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	result, err := store.PreTranslate(&doc, tm.Options{MinSimilarity: 0.99})
+	if err != nil {
+		t.Fatal("Error pre-translating: ", err)
+	}
+	if result != (tm.Result{}) {
+		t.Errorf("Expected no matches but got %+v", result)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"Open", "Open", 1},
+		{"Open", "Opens", 0.8},
+		{"файл", "файлы", 0.8},
+		{"abc", "xyz", 0},
+		{"", "", 1},
+	}
+	for _, c := range cases {
+		if actual := tm.Similarity(c.a, c.b); actual != c.expected {
+			t.Errorf("Expected %v for '%v' and '%v' but got %v", c.expected, c.a, c.b, actual)
+		}
+	}
+}
+
+func TestOpenInvalidStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.tmx")
+	if err := os.WriteFile(path, []byte("not tmx"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := tm.Open(path)
+	if _, ok := err.(tm.StoreError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", tm.StoreError{}, err, err)
+	}
+}
@@ -0,0 +1,214 @@
+package tmx
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+// Unit is a translation unit, with a source string and its translation into each language.
+// Plural entries have a unit per plural category, where the source is the msgid for the one category and the msgid_plural otherwise.
+type Unit struct {
+	IsContextual bool
+	Context      string
+	Source       string
+
+	// PluralCategory is the plural category of the translations, for units from plural entries.
+	PluralCategory string
+
+	Translations map[language.Tag]string
+}
+
+type unitKey struct {
+	isContextual   bool
+	context        string
+	source         string
+	pluralCategory string
+}
+
+func (u *Unit) key() unitKey {
+	return unitKey{u.IsContextual, u.Context, u.Source, u.PluralCategory}
+}
+
+// Translation finds the translation into the language, falling back to a translation into another variant of the same language
+// written in the same script, like pt-BR for pt, or zh-CN for zh-Hans, but never zh-Hant for zh-Hans.
+func (u *Unit) Translation(tag language.Tag) (string, bool) {
+	if t, ok := u.Translations[tag]; ok {
+		return t, true
+	}
+
+	// sorted, so that the same candidate is always picked
+	base, _ := tag.Base()
+	script, _ := tag.Script()
+	for _, candidate := range sortedTags(u.Translations) {
+		candidateBase, _ := candidate.Base()
+		candidateScript, _ := candidate.Script()
+		if candidateBase == base && candidateScript == script {
+			return u.Translations[candidate], true
+		}
+	}
+	return "", false
+}
+
+func sortedTags(translations map[language.Tag]string) []language.Tag {
+	tags := make([]language.Tag, 0, len(translations))
+	for tag := range translations {
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, func(a, b language.Tag) int { return strings.Compare(a.String(), b.String()) })
+	return tags
+}
+
+// Memory is a translation memory, which is what TMX files have.
+type Memory struct {
+	// SourceLanguage is the language of each unit's Source.
+	SourceLanguage language.Tag
+	Units          []Unit
+
+	index map[unitKey]int
+}
+
+func NewMemory(sourceLanguage language.Tag) Memory {
+	return Memory{SourceLanguage: sourceLanguage}
+}
+
+// Find finds the unit for the source string.
+func (m *Memory) Find(isContextual bool, context string, source string, pluralCategory string) (*Unit, bool) {
+	m.buildIndex()
+	if i, ok := m.index[unitKey{isContextual, context, source, pluralCategory}]; ok {
+		return &m.Units[i], true
+	}
+	return nil, false
+}
+
+func (m *Memory) buildIndex() {
+	if m.index != nil && len(m.index) == len(m.Units) {
+		return
+	}
+	m.index = make(map[unitKey]int, len(m.Units))
+	for i := range m.Units {
+		m.index[m.Units[i].key()] = i
+	}
+}
+
+// AddTranslation adds the translation to the unit for the source string, replacing any earlier translation into the language.
+func (m *Memory) AddTranslation(isContextual bool, context string, source string, pluralCategory string, tag language.Tag, translation string) {
+	unit, ok := m.Find(isContextual, context, source, pluralCategory)
+	if !ok {
+		m.Units = append(m.Units, Unit{
+			IsContextual:   isContextual,
+			Context:        context,
+			Source:         source,
+			PluralCategory: pluralCategory,
+			Translations:   make(map[language.Tag]string),
+		})
+		unit = &m.Units[len(m.Units)-1]
+		m.index[unit.key()] = len(m.Units) - 1
+	}
+	unit.Translations[tag] = translation
+}
+
+// AddDocument adds the translations of the document, in its header's language. Plural entries add a unit per plural category,
+// rather than per msgstr[n], so that languages that number their msgstr[n] differently still share units.
+// Untranslated, fuzzy, and obsolete entries are skipped, as are plural entries that are only partially translated.
+func (m *Memory) AddDocument(d *gettext.Document) error {
+	plurals := sync.OnceValues(d.Header.PluralMapping)
+	tag := d.Header.Tag
+
+	for i, entry := range d.Entries {
+		if i == 0 || entry.IsObsolete || entry.Header.IsFuzzy() {
+			continue
+		}
+
+		if !entry.IsPlural {
+			if len(entry.Value) > 0 {
+				m.AddTranslation(entry.IsContextual, entry.Context, entry.Id, "", tag, entry.Value)
+			}
+			continue
+		}
+
+		plurals, err := plurals()
+		if err != nil {
+			return err
+		}
+		if err := plurals.ValidatePluralCount(&entry); err != nil {
+			return err
+		}
+		if !entry.IsTranslated() {
+			continue
+		}
+		for _, pluralType := range plurals.Categories() {
+			value := plurals.Value(entry.PluralValues, pluralType)
+			m.AddTranslation(entry.IsContextual, entry.Context, entry.PluralSource(pluralType), pluralType.Category(), tag, value)
+		}
+	}
+	return nil
+}
+
+// Apply fills the untranslated entries of the document with the translations into the document's language,
+// returning the number of entries filled. Each msgstr[n] of a plural entry is filled with the unit of the plural category it's for,
+// even if the memory doesn't have units for all of them.
+func (m *Memory) Apply(d *gettext.Document) (int, error) {
+	plurals, err := d.Header.PluralMapping()
+	if err != nil {
+		return 0, err
+	}
+	tag := d.Header.Tag
+
+	filled := 0
+	for i := 1; i < len(d.Entries); i++ {
+		entry := d.Entries[i]
+		if entry.IsObsolete {
+			continue
+		}
+
+		var update gettext.EntryUpdate
+		if !entry.IsPlural {
+			if len(entry.Value) > 0 {
+				continue
+			}
+			unit, ok := m.Find(entry.IsContextual, entry.Context, entry.Id, "")
+			if !ok {
+				continue
+			}
+			translation, ok := unit.Translation(tag)
+			if !ok {
+				continue
+			}
+			update.Value = &translation
+		} else {
+			if slices.ContainsFunc(entry.PluralValues, func(v string) bool { return len(v) > 0 }) {
+				continue
+			}
+			if err := plurals.ValidatePluralCount(&entry); err != nil {
+				return filled, err
+			}
+
+			values := make([]string, len(entry.PluralValues))
+			found := false
+			for i := range values {
+				pluralType := plurals.PluralType(i)
+				if unit, ok := m.Find(entry.IsContextual, entry.Context, entry.PluralSource(pluralType), pluralType.Category()); ok {
+					values[i], ok = unit.Translation(tag)
+					found = found || ok
+				}
+			}
+			if !found {
+				continue
+			}
+			update.PluralValues = values
+		}
+
+		changed, err := d.UpdateEntry(entry.EntryKey, update)
+		if err != nil {
+			return filled, err
+		}
+		if changed {
+			filled++
+		}
+	}
+	return filled, nil
+}
@@ -0,0 +1,192 @@
+package tmx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+type Options struct {
+	// SourceLanguage is the language of the template's msgids, defaulting to English.
+	SourceLanguage language.Tag
+}
+
+type ConversionError struct {
+	Reason          string
+	UnderlyingError error
+}
+
+func (e ConversionError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to convert TMX: %v Underlying error: %v", e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to convert TMX: %v", e.Reason)
+}
+
+// names of the po-specific bits of information, which are kept as props of each tu
+const (
+	msgctxtType        = "x-po-msgctxt"
+	pluralCategoryType = "x-po-plural-category"
+)
+
+type tmx struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTmf                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	Datatype            string `xml:"datatype,attr"`
+}
+
+type tmxUnit struct {
+	Props    []tmxProp    `xml:"prop"`
+	Variants []tmxVariant `xml:"tuv"`
+}
+
+type tmxProp struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type tmxVariant struct {
+	Lang    string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Segment string `xml:"seg"`
+}
+
+// WriteDocuments writes the translations of the documents as TMX, with a tuv for the template's language and the language of each document's header.
+// Untranslated, fuzzy, and obsolete entries are skipped, as are plural entries that are only partially translated.
+func WriteDocuments(w io.Writer, options Options, documents ...*gettext.Document) error {
+	m := NewMemory(options.SourceLanguage)
+	for _, d := range documents {
+		if err := m.AddDocument(d); err != nil {
+			return err
+		}
+	}
+	return Write(w, &m)
+}
+
+// Write writes the translation memory as TMX 1.4, with a tu per unit.
+// Since TMX has no place for them, the msgctxt and plural category are kept as "x-po-msgctxt" and "x-po-plural-category" props.
+func Write(w io.Writer, m *Memory) error {
+	sourceLanguage := m.SourceLanguage
+	if sourceLanguage == language.Und {
+		sourceLanguage = language.English
+	}
+
+	doc := tmx{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "golocalization",
+			CreationToolVersion: "1.0",
+			SegType:             "sentence",
+			OTmf:                "po",
+			AdminLang:           "en",
+			SrcLang:             sourceLanguage.String(),
+			Datatype:            "plaintext",
+		},
+	}
+
+	for _, unit := range m.Units {
+		var tu tmxUnit
+		if unit.IsContextual {
+			tu.Props = append(tu.Props, tmxProp{msgctxtType, unit.Context})
+		}
+		if len(unit.PluralCategory) > 0 {
+			tu.Props = append(tu.Props, tmxProp{pluralCategoryType, unit.PluralCategory})
+		}
+
+		tu.Variants = append(tu.Variants, tmxVariant{sourceLanguage.String(), unit.Source})
+		for _, tag := range sortedTags(unit.Translations) {
+			tu.Variants = append(tu.Variants, tmxVariant{tag.String(), unit.Translations[tag]})
+		}
+		doc.Units = append(doc.Units, tu)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Read reads a TMX file, like one from a CAT tool, as a translation memory.
+// The source of each tu is the tuv of the header's srclang, or the first tuv if the srclang is "*all*".
+// Inline markup of segments, like <ph>, is skipped, keeping only the text.
+func Read(r io.Reader) (Memory, error) {
+	var doc tmx
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return Memory{}, ConversionError{"Unable to read TMX.", err}
+	}
+
+	var sourceLanguage language.Tag
+	if doc.Header.SrcLang != "*all*" {
+		var err error
+		if sourceLanguage, err = language.Parse(doc.Header.SrcLang); err != nil {
+			return Memory{}, ConversionError{fmt.Sprint("Invalid srclang: ", doc.Header.SrcLang), err}
+		}
+	}
+
+	m := NewMemory(sourceLanguage)
+	for _, tu := range doc.Units {
+		var isContextual bool
+		var context, pluralCategory string
+		for _, prop := range tu.Props {
+			switch prop.Type {
+			case msgctxtType:
+				isContextual, context = true, prop.Text
+			case pluralCategoryType:
+				pluralCategory = prop.Text
+			}
+		}
+
+		tags := make([]language.Tag, len(tu.Variants))
+		sourceIndex := -1
+		for i, tuv := range tu.Variants {
+			tag, err := language.Parse(tuv.Lang)
+			if err != nil {
+				return Memory{}, ConversionError{fmt.Sprint("Invalid xml:lang: ", tuv.Lang), err}
+			}
+			tags[i] = tag
+			if sourceIndex < 0 && (tag == sourceLanguage || (sourceLanguage == language.Und && i == 0)) {
+				sourceIndex = i
+			}
+		}
+		// units without a source are of no use for finding translations
+		if sourceIndex < 0 {
+			continue
+		}
+
+		for i, tuv := range tu.Variants {
+			if i == sourceIndex || len(tuv.Segment) == 0 {
+				continue
+			}
+			m.AddTranslation(isContextual, context, tu.Variants[sourceIndex].Segment, pluralCategory, tags[i], tuv.Segment)
+		}
+	}
+	return m, nil
+}
+
+// Apply fills the untranslated entries of the document with the translations of a TMX file, as Memory.Apply does, returning the number of entries filled.
+func Apply(r io.Reader, d *gettext.Document) (int, error) {
+	m, err := Read(r)
+	if err != nil {
+		return 0, err
+	}
+	return m.Apply(d)
+}
@@ -0,0 +1,272 @@
+package tmx_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/tmx"
+	"golang.org/x/text/language"
+)
+
+const russianText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgctxt "app.name"
+msgid "Files"
+msgstr "Файлы"
+
+#, fuzzy
+msgid "Save & close"
+msgstr "Сохр"
+
+msgid "Open <b>now</b> & later"
+msgstr "Открыть <b>сейчас</b> & потом"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+
+msgid "Open"
+msgstr ""
+
+#~ msgid "Old"
+#~ msgstr "Старый"
+`
+
+const japaneseText = `msgid ""
+msgstr "Language: ja\n"
+
+msgctxt "app.name"
+msgid "Files"
+msgstr "ファイル"
+
+msgid "Open"
+msgstr "開く"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d 個のファイル"
+`
+
+const expectedTmx = `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="golocalization" creationtoolversion="1.0" segtype="sentence" o-tmf="po" adminlang="en" srclang="en" datatype="plaintext"></header>
+  <body>
+    <tu>
+      <prop type="x-po-msgctxt">app.name</prop>
+      <tuv xml:lang="en">
+        <seg>Files</seg>
+      </tuv>
+      <tuv xml:lang="ja">
+        <seg>ファイル</seg>
+      </tuv>
+      <tuv xml:lang="ru">
+        <seg>Файлы</seg>
+      </tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en">
+        <seg>Open &lt;b&gt;now&lt;/b&gt; &amp; later</seg>
+      </tuv>
+      <tuv xml:lang="ru">
+        <seg>Открыть &lt;b&gt;сейчас&lt;/b&gt; &amp; потом</seg>
+      </tuv>
+    </tu>
+    <tu>
+      <prop type="x-po-plural-category">one</prop>
+      <tuv xml:lang="en">
+        <seg>%d file</seg>
+      </tuv>
+      <tuv xml:lang="ru">
+        <seg>%d файл</seg>
+      </tuv>
+    </tu>
+    <tu>
+      <prop type="x-po-plural-category">few</prop>
+      <tuv xml:lang="en">
+        <seg>%d files</seg>
+      </tuv>
+      <tuv xml:lang="ru">
+        <seg>%d файла</seg>
+      </tuv>
+    </tu>
+    <tu>
+      <prop type="x-po-plural-category">many</prop>
+      <tuv xml:lang="en">
+        <seg>%d files</seg>
+      </tuv>
+      <tuv xml:lang="ru">
+        <seg>%d файлов</seg>
+      </tuv>
+    </tu>
+    <tu>
+      <prop type="x-po-plural-category">other</prop>
+      <tuv xml:lang="en">
+        <seg>%d files</seg>
+      </tuv>
+      <tuv xml:lang="ja">
+        <seg>%d 個のファイル</seg>
+      </tuv>
+      <tuv xml:lang="ru">
+        <seg>%d файлов</seg>
+      </tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en">
+        <seg>Open</seg>
+      </tuv>
+      <tuv xml:lang="ja">
+        <seg>開く</seg>
+      </tuv>
+    </tu>
+  </body>
+</tmx>
+`
+
+func TestWriteDocuments(t *testing.T) {
+	ru, err := gettext.ParseDocumentString(russianText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	ja, err := gettext.ParseDocumentString(japaneseText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmx.WriteDocuments(&buf, tmx.Options{}, &ru, &ja); err != nil {
+		t.Fatal("Error writing TMX: ", err)
+	}
+	if buf.String() != expectedTmx {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedTmx, buf.String())
+	}
+}
+
+func TestApply(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: ru\n"
+
+msgctxt "app.name"
+msgid "Files"
+msgstr ""
+
+msgid "Open <b>now</b> & later"
+msgstr "Открыть <b>сразу</b> & потом"
+
+# translated in another app
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+msgstr[2] ""
+
+msgid "Files"
+msgstr ""
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	changes, err := tmx.Apply(strings.NewReader(expectedTmx), &doc)
+	if err != nil {
+		t.Fatal("Error applying TMX: ", err)
+	}
+	if changes != 2 {
+		t.Errorf("Expected 2 changes but got %v", changes)
+	}
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal("Error writing document: ", err)
+	}
+	expected := `msgid ""
+msgstr "Language: ru\n"
+
+msgctxt "app.name"
+msgid "Files"
+msgstr "Файлы"
+
+msgid "Open <b>now</b> & later"
+msgstr "Открыть <b>сразу</b> & потом"
+
+# translated in another app
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+
+msgid "Files"
+msgstr ""
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buf.String())
+	}
+}
+
+func TestReadFromCatTool(t *testing.T) {
+	// other tools use regional languages, have more than one language in a tu, and have inline markup
+	m, err := tmx.Read(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="SomeTool" segtype="sentence" o-tmf="tm" adminlang="en-US" srclang="en-US" datatype="plaintext"/>
+  <body>
+    <tu tuid="1">
+      <tuv xml:lang="de-DE"><seg>Öffnen</seg></tuv>
+      <tuv xml:lang="en-US"><seg>Open <ph x="1">&lt;b&gt;</ph>now</seg></tuv>
+      <tuv xml:lang="pt-BR"><seg>Abrir agora</seg></tuv>
+    </tu>
+    <tu tuid="2">
+      <tuv xml:lang="fr-FR"><seg>Sans source</seg></tuv>
+    </tu>
+  </body>
+</tmx>`))
+	if err != nil {
+		t.Fatal("Error reading TMX: ", err)
+	}
+
+	if len(m.Units) != 1 {
+		t.Fatalf("Expected 1 unit but got %v: %+v", len(m.Units), m.Units)
+	}
+	unit, ok := m.Find(false, "", "Open now", "")
+	if !ok {
+		t.Fatalf("Expected a unit for 'Open now' but got %+v", m.Units)
+	}
+	doc, err := gettext.ParseDocumentString(`msgid ""
+msgstr "Language: pt\n"
+`)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	if translation, _ := unit.Translation(doc.Header.Tag); translation != "Abrir agora" {
+		t.Errorf("Expected 'Abrir agora' but got '%v'", translation)
+	}
+}
+
+func TestUnitTranslation_FallsBackToSameScript(t *testing.T) {
+	unit := tmx.Unit{Source: "Open", Translations: map[language.Tag]string{
+		language.MustParse("zh-Hans"): "打开",
+		language.MustParse("sr-Latn"): "Otvori",
+	}}
+
+	if translation, ok := unit.Translation(language.MustParse("zh-CN")); !ok || translation != "打开" {
+		t.Errorf("Expected zh-Hans for zh-CN, but got '%v'", translation)
+	}
+	for _, tag := range []string{"zh-Hant", "zh-TW", "sr-Cyrl", "sr"} {
+		if translation, ok := unit.Translation(language.MustParse(tag)); ok {
+			t.Errorf("Expected no translation for %v, since it's written in another script, but got '%v'", tag, translation)
+		}
+	}
+}
+
+func TestReadError(t *testing.T) {
+	_, err := tmx.Read(strings.NewReader(`<tmx version="1.4"><header srclang="not a language"/><body/></tmx>`))
+	if _, ok := err.(tmx.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", tmx.ConversionError{}, err, err)
+	}
+}
@@ -0,0 +1,199 @@
+package xliff
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"golang.org/x/text/language"
+)
+
+type Options struct {
+	// SourceLanguage is the language of the msgids, which po files don't record. English is used if not set.
+	SourceLanguage language.Tag
+}
+
+type ConversionError struct {
+	ID              string
+	Reason          string
+	UnderlyingError error
+}
+
+func (e ConversionError) Error() string {
+	if e.UnderlyingError != nil {
+		return fmt.Sprintf("Failed to convert '%v': %v Underlying error: %v", e.ID, e.Reason, e.UnderlyingError)
+	}
+	return fmt.Sprintf("Failed to convert '%v': %v", e.ID, e.Reason)
+}
+
+// Read reads either XLIFF 1.2 or 2.0, based on the version of the document.
+func Read(r io.Reader) (gettext.Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return gettext.Document{}, err
+	}
+
+	var root struct {
+		Version string `xml:"version,attr"`
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return gettext.Document{}, ConversionError{Reason: "Unable to read XLIFF.", UnderlyingError: err}
+	}
+
+	switch root.Version {
+	case "1.2":
+		return ReadXliff12(bytes.NewReader(data))
+	case "2.0", "2.1":
+		return ReadXliff20(bytes.NewReader(data))
+	}
+	return gettext.Document{}, ConversionError{Reason: fmt.Sprint("Unsupported XLIFF version: ", root.Version)}
+}
+
+// names of the po-specific bits of information, which both versions keep in their own ways
+const (
+	msgctxtType     = "x-po-msgctxt"
+	msgidPluralType = "x-po-msgid-plural"
+	flagsType       = "x-po-flags"
+	headerType      = "x-po-header"
+)
+
+// poUnit is what both versions convert entries to and from
+type poUnit struct {
+	key    gettext.EntryKey
+	header gettext.EntryHeader
+
+	// sources has the msgid, then the msgid_plural for each remaining plural form.
+	// targets has the msgstr, or the msgstr[n] of each plural form.
+	sources []string
+	targets []string
+}
+
+// flags are the flags other than fuzzy, which both versions have their own way of marking
+func (u *poUnit) flags() []string {
+	return slices.DeleteFunc(slices.Clone(u.header.Flags), func(flag string) bool {
+		return flag == "fuzzy"
+	})
+}
+
+// unitsFromDocument skips the header, which is returned separately, as well as obsolete entries, which aren't used
+func unitsFromDocument(d *gettext.Document) (headerValue string, units []poUnit) {
+	for i, entry := range d.Entries {
+		if i == 0 {
+			headerValue = entry.Value
+			continue
+		}
+		if entry.IsObsolete {
+			continue
+		}
+
+		unit := poUnit{key: entry.EntryKey, header: entry.Header}
+		if entry.IsPlural {
+			unit.targets = entry.PluralValues
+			for i := range entry.PluralValues {
+				if i == 0 {
+					unit.sources = append(unit.sources, entry.Id)
+				} else {
+					unit.sources = append(unit.sources, entry.PluralId)
+				}
+			}
+		} else {
+			unit.sources = []string{entry.Id}
+			unit.targets = []string{entry.Value}
+		}
+		units = append(units, unit)
+	}
+	return
+}
+
+func documentFromUnits(headerValue string, targetLanguage string, units []poUnit) (gettext.Document, error) {
+	entries := make([]gettext.Entry, 0, len(units)+1)
+	if len(headerValue) > 0 {
+		entries = append(entries, gettext.NewHeaderEntryFromValue(headerValue))
+	} else {
+		tag, err := language.Parse(targetLanguage)
+		if err != nil {
+			return gettext.Document{}, ConversionError{Reason: fmt.Sprint("Unable to parse target language: ", targetLanguage), UnderlyingError: err}
+		}
+		entries = append(entries, gettext.NewHeaderEntry(tag))
+	}
+
+	for _, unit := range units {
+		if len(unit.sources) == 0 {
+			return gettext.Document{}, ConversionError{Reason: "Found a unit without a source."}
+		}
+
+		key := unit.key
+		key.Id = unit.sources[0]
+		if key.IsPlural {
+			if len(key.PluralId) == 0 && len(unit.sources) > 1 {
+				key.PluralId = unit.sources[1]
+			}
+			entries = append(entries, gettext.NewEntry(key, unit.header, "", unit.targets))
+		} else {
+			var value string
+			if len(unit.targets) > 0 {
+				value = unit.targets[0]
+			}
+			entries = append(entries, gettext.NewEntry(key, unit.header, value, nil))
+		}
+	}
+
+	return gettext.CreateDocument(entries)
+}
+
+// splitReference splits references like "main.go:12" into the file and line
+func splitReference(reference string) (file string, line string) {
+	i := strings.LastIndex(reference, ":")
+	if i < 0 || strings.Trim(reference[i+1:], "0123456789") != "" || i == len(reference)-1 {
+		return reference, ""
+	}
+	return reference[:i], reference[i+1:]
+}
+
+func joinReference(file string, line string) string {
+	if len(line) == 0 {
+		return file
+	}
+	return fmt.Sprint(file, ":", line)
+}
+
+func joinFlags(flags []string) string {
+	return strings.Join(flags, ", ")
+}
+
+func splitFlags(flags string) []string {
+	var result []string
+	for _, flag := range strings.Split(flags, ",") {
+		if flag = strings.TrimSpace(flag); len(flag) > 0 {
+			result = append(result, flag)
+		}
+	}
+	return result
+}
+
+var defaultSourceLanguage = language.English
+
+func languageOrDefault(tag language.Tag, defaultTag language.Tag) language.Tag {
+	if tag == language.Und {
+		return defaultTag
+	}
+	return tag
+}
+
+func writeXml(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,283 @@
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+const (
+	xliff12Namespace = "urn:oasis:names:tc:xliff:document:1.2"
+
+	// the restype translate-toolkit uses for groups of plural forms
+	xliff12PluralRestype = "x-gettext-plurals"
+
+	xliff12StateTranslated  = "translated"
+	xliff12StateNeedsReview = "needs-review-translation"
+)
+
+type xliff12 struct {
+	XMLName xml.Name      `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string        `xml:"version,attr"`
+	Files   []xliff12File `xml:"file"`
+}
+
+type xliff12File struct {
+	Original       string         `xml:"original,attr"`
+	SourceLanguage string         `xml:"source-language,attr"`
+	TargetLanguage string         `xml:"target-language,attr,omitempty"`
+	Datatype       string         `xml:"datatype,attr"`
+	Header         *xliff12Header `xml:"header"`
+	Body           xliff12Body    `xml:"body"`
+}
+
+type xliff12Header struct {
+	Notes []xliff12Note `xml:"note"`
+}
+
+type xliff12Body struct {
+	// trans-units and groups, kept in order
+	Elements []xliff12Element `xml:",any"`
+}
+
+// xliff12Element is either a trans-unit or a group, since the order of both needs to be kept
+type xliff12Element struct {
+	XMLName xml.Name
+	ID      string `xml:"id,attr"`
+	Restype string `xml:"restype,attr,omitempty"`
+	Space   string `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+
+	Source *string        `xml:"source"`
+	Target *xliff12Target `xml:"target"`
+
+	ContextGroups []xliff12ContextGroup `xml:"context-group"`
+	Notes         []xliff12Note         `xml:"note"`
+
+	// for groups
+	TransUnits []xliff12Element `xml:"trans-unit"`
+}
+
+type xliff12Target struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+type xliff12ContextGroup struct {
+	Name     string           `xml:"name,attr,omitempty"`
+	Purpose  string           `xml:"purpose,attr"`
+	Contexts []xliff12Context `xml:"context"`
+}
+
+type xliff12Context struct {
+	Type string `xml:"context-type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type xliff12Note struct {
+	From string `xml:"from,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+// WriteXliff12 writes the document as XLIFF 1.2, in the way translate-toolkit's po2xliff does.
+// Plural entries are groups of trans-units, one per plural form, and fuzzy entries have the needs-review-translation state.
+// The po header and obsolete entries aren't translated, so the header is kept as a note and obsolete entries are skipped.
+func WriteXliff12(w io.Writer, d *gettext.Document, options Options) error {
+	headerValue, units := unitsFromDocument(d)
+
+	file := xliff12File{
+		Original:       "messages.po",
+		SourceLanguage: languageOrDefault(options.SourceLanguage, defaultSourceLanguage).String(),
+		TargetLanguage: d.Header.Tag.String(),
+		Datatype:       "po",
+		Header:         &xliff12Header{[]xliff12Note{{headerType, headerValue}}},
+	}
+
+	for i, unit := range units {
+		id := strconv.Itoa(i + 1)
+		if !unit.key.IsPlural {
+			element := createXliff12TransUnit(id, &unit, 0)
+			element.ContextGroups = createXliff12ContextGroups(&unit)
+			element.Notes = createXliff12Notes(&unit)
+			file.Body.Elements = append(file.Body.Elements, element)
+			continue
+		}
+
+		group := xliff12Element{
+			XMLName:       xml.Name{Local: "group"},
+			ID:            id,
+			Restype:       xliff12PluralRestype,
+			Space:         "preserve",
+			ContextGroups: createXliff12ContextGroups(&unit),
+			Notes:         createXliff12Notes(&unit),
+		}
+		for i := range unit.targets {
+			group.TransUnits = append(group.TransUnits, createXliff12TransUnit(fmt.Sprintf("%v[%v]", id, i), &unit, i))
+		}
+		file.Body.Elements = append(file.Body.Elements, group)
+	}
+
+	return writeXml(w, xliff12{Version: "1.2", Files: []xliff12File{file}})
+}
+
+func createXliff12TransUnit(id string, unit *poUnit, index int) xliff12Element {
+	element := xliff12Element{
+		XMLName: xml.Name{Local: "trans-unit"},
+		ID:      id,
+		Space:   "preserve",
+		Source:  &unit.sources[index],
+	}
+
+	if target := unit.targets[index]; unit.header.IsFuzzy() {
+		element.Target = &xliff12Target{xliff12StateNeedsReview, target}
+	} else if len(target) > 0 {
+		element.Target = &xliff12Target{xliff12StateTranslated, target}
+	}
+
+	return element
+}
+
+func createXliff12ContextGroups(unit *poUnit) []xliff12ContextGroup {
+	var groups []xliff12ContextGroup
+
+	entryGroup := xliff12ContextGroup{Name: "po-entry", Purpose: "information"}
+	if unit.key.IsContextual {
+		entryGroup.Contexts = append(entryGroup.Contexts, xliff12Context{msgctxtType, unit.key.Context})
+	}
+	if unit.key.IsPlural {
+		entryGroup.Contexts = append(entryGroup.Contexts, xliff12Context{msgidPluralType, unit.key.PluralId})
+	}
+	if flags := unit.flags(); len(flags) > 0 {
+		entryGroup.Contexts = append(entryGroup.Contexts, xliff12Context{flagsType, joinFlags(flags)})
+	}
+	if len(entryGroup.Contexts) > 0 {
+		groups = append(groups, entryGroup)
+	}
+
+	for _, reference := range unit.header.References {
+		file, line := splitReference(reference)
+		group := xliff12ContextGroup{Name: "po-reference", Purpose: "location"}
+		group.Contexts = append(group.Contexts, xliff12Context{"sourcefile", file})
+		if len(line) > 0 {
+			group.Contexts = append(group.Contexts, xliff12Context{"linenumber", line})
+		}
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+func createXliff12Notes(unit *poUnit) []xliff12Note {
+	var notes []xliff12Note
+	for _, comment := range unit.header.TranslatorComments {
+		notes = append(notes, xliff12Note{"translator", comment})
+	}
+	for _, comment := range unit.header.ExtractedComments {
+		notes = append(notes, xliff12Note{"developer", comment})
+	}
+	return notes
+}
+
+func ReadXliff12(r io.Reader) (gettext.Document, error) {
+	var x xliff12
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return gettext.Document{}, ConversionError{Reason: "Unable to read XLIFF 1.2.", UnderlyingError: err}
+	}
+	if len(x.Files) != 1 {
+		return gettext.Document{}, ConversionError{Reason: fmt.Sprint("Expected exactly one file, but found ", len(x.Files))}
+	}
+	file := x.Files[0]
+
+	var headerValue string
+	if file.Header != nil {
+		for _, note := range file.Header.Notes {
+			if note.From == headerType {
+				headerValue = note.Text
+			}
+		}
+	}
+
+	units := make([]poUnit, 0, len(file.Body.Elements))
+	for _, element := range file.Body.Elements {
+		unit := poUnit{}
+		readXliff12ContextGroups(&unit, element.ContextGroups)
+		readXliff12Notes(&unit, element.Notes)
+
+		switch element.XMLName.Local {
+		case "trans-unit":
+			if err := readXliff12TransUnit(&unit, &element); err != nil {
+				return gettext.Document{}, err
+			}
+		case "group":
+			unit.key.IsPlural = true
+			for _, transUnit := range element.TransUnits {
+				if err := readXliff12TransUnit(&unit, &transUnit); err != nil {
+					return gettext.Document{}, err
+				}
+			}
+		default:
+			continue
+		}
+
+		units = append(units, unit)
+	}
+
+	return documentFromUnits(headerValue, file.TargetLanguage, units)
+}
+
+func readXliff12TransUnit(unit *poUnit, element *xliff12Element) error {
+	if element.Source == nil {
+		return ConversionError{ID: element.ID, Reason: "Found a trans-unit without a source."}
+	}
+	unit.sources = append(unit.sources, *element.Source)
+
+	var target string
+	if element.Target != nil {
+		target = element.Target.Text
+		if element.Target.State == xliff12StateNeedsReview && !unit.header.IsFuzzy() {
+			unit.header.Flags = append([]string{"fuzzy"}, unit.header.Flags...)
+		}
+	}
+	unit.targets = append(unit.targets, target)
+
+	return nil
+}
+
+func readXliff12ContextGroups(unit *poUnit, groups []xliff12ContextGroup) {
+	for _, group := range groups {
+		var file, line string
+		for _, context := range group.Contexts {
+			switch context.Type {
+			case msgctxtType:
+				unit.key.IsContextual = true
+				unit.key.Context = context.Text
+			case msgidPluralType:
+				unit.key.PluralId = context.Text
+			case flagsType:
+				unit.header.Flags = append(unit.header.Flags, splitFlags(context.Text)...)
+			case "sourcefile":
+				file = context.Text
+			case "linenumber":
+				line = context.Text
+			}
+		}
+
+		if group.Purpose == "location" && len(file) > 0 {
+			unit.header.References = append(unit.header.References, joinReference(file, line))
+		}
+	}
+}
+
+func readXliff12Notes(unit *poUnit, notes []xliff12Note) {
+	for _, note := range notes {
+		switch note.From {
+		case "developer":
+			unit.header.ExtractedComments = append(unit.header.ExtractedComments, note.Text)
+		default:
+			// notes are usually from translators, so any that aren't marked as from developers are kept as translator comments
+			unit.header.TranslatorComments = append(unit.header.TranslatorComments, note.Text)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Timiz0r/golocalization/gettext"
+)
+
+const (
+	xliff20PluralType = "po:plural"
+
+	xliff20StateInitial    = "initial"
+	xliff20StateTranslated = "translated"
+	xliff20SubStateFuzzy   = "po:fuzzy"
+
+	xliff20TranslatorCategory = "translator"
+	xliff20DeveloperCategory  = "developer"
+	xliff20LocationCategory   = "location"
+)
+
+type xliff20 struct {
+	XMLName xml.Name      `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string        `xml:"version,attr"`
+	SrcLang string        `xml:"srcLang,attr"`
+	TrgLang string        `xml:"trgLang,attr,omitempty"`
+	Files   []xliff20File `xml:"file"`
+}
+
+type xliff20File struct {
+	ID    string        `xml:"id,attr"`
+	Space string        `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Notes *xliff20Notes `xml:"notes"`
+
+	// units and groups, kept in order
+	Elements []xliff20Element `xml:",any"`
+}
+
+// xliff20Element is either a unit or a group, since the order of both needs to be kept
+type xliff20Element struct {
+	XMLName xml.Name
+	ID      string        `xml:"id,attr"`
+	Type    string        `xml:"type,attr,omitempty"`
+	Notes   *xliff20Notes `xml:"notes"`
+
+	Segments []xliff20Segment `xml:"segment"`
+
+	// for groups
+	Units []xliff20Element `xml:"unit"`
+}
+
+type xliff20Notes struct {
+	Notes []xliff20Note `xml:"note"`
+}
+
+type xliff20Note struct {
+	Category string `xml:"category,attr,omitempty"`
+	Text     string `xml:",chardata"`
+}
+
+type xliff20Segment struct {
+	State    string  `xml:"state,attr,omitempty"`
+	SubState string  `xml:"subState,attr,omitempty"`
+	Source   string  `xml:"source"`
+	Target   *string `xml:"target"`
+}
+
+// WriteXliff20 writes the document as XLIFF 2.0.
+// Plural entries are groups of units, one per plural form, and fuzzy entries have the initial state with a po:fuzzy subState.
+// msgctxt, flags, and the like are kept as notes, with the po header kept as a note of the file. Obsolete entries are skipped.
+func WriteXliff20(w io.Writer, d *gettext.Document, options Options) error {
+	headerValue, units := unitsFromDocument(d)
+
+	file := xliff20File{
+		ID:    "f1",
+		Space: "preserve",
+		Notes: &xliff20Notes{[]xliff20Note{{headerType, headerValue}}},
+	}
+
+	for i, unit := range units {
+		id := strconv.Itoa(i + 1)
+		if !unit.key.IsPlural {
+			element := createXliff20Unit(id, &unit, 0)
+			element.Notes = createXliff20Notes(&unit)
+			file.Elements = append(file.Elements, element)
+			continue
+		}
+
+		group := xliff20Element{
+			XMLName: xml.Name{Local: "group"},
+			ID:      id,
+			Type:    xliff20PluralType,
+			Notes:   createXliff20Notes(&unit),
+		}
+		for i := range unit.targets {
+			group.Units = append(group.Units, createXliff20Unit(fmt.Sprintf("%v-%v", id, i), &unit, i))
+		}
+		file.Elements = append(file.Elements, group)
+	}
+
+	return writeXml(w, xliff20{
+		Version: "2.0",
+		SrcLang: languageOrDefault(options.SourceLanguage, defaultSourceLanguage).String(),
+		TrgLang: d.Header.Tag.String(),
+		Files:   []xliff20File{file},
+	})
+}
+
+func createXliff20Unit(id string, unit *poUnit, index int) xliff20Element {
+	segment := xliff20Segment{State: xliff20StateInitial, Source: unit.sources[index]}
+
+	target := unit.targets[index]
+	if unit.header.IsFuzzy() {
+		segment.SubState = xliff20SubStateFuzzy
+		segment.Target = &target
+	} else if len(target) > 0 {
+		segment.State = xliff20StateTranslated
+		segment.Target = &target
+	}
+
+	return xliff20Element{
+		XMLName:  xml.Name{Local: "unit"},
+		ID:       id,
+		Segments: []xliff20Segment{segment},
+	}
+}
+
+func createXliff20Notes(unit *poUnit) *xliff20Notes {
+	var notes []xliff20Note
+	if unit.key.IsContextual {
+		notes = append(notes, xliff20Note{msgctxtType, unit.key.Context})
+	}
+	if unit.key.IsPlural {
+		notes = append(notes, xliff20Note{msgidPluralType, unit.key.PluralId})
+	}
+	if flags := unit.flags(); len(flags) > 0 {
+		notes = append(notes, xliff20Note{flagsType, joinFlags(flags)})
+	}
+	for _, reference := range unit.header.References {
+		notes = append(notes, xliff20Note{xliff20LocationCategory, reference})
+	}
+	for _, comment := range unit.header.TranslatorComments {
+		notes = append(notes, xliff20Note{xliff20TranslatorCategory, comment})
+	}
+	for _, comment := range unit.header.ExtractedComments {
+		notes = append(notes, xliff20Note{xliff20DeveloperCategory, comment})
+	}
+
+	if len(notes) == 0 {
+		return nil
+	}
+	return &xliff20Notes{notes}
+}
+
+func ReadXliff20(r io.Reader) (gettext.Document, error) {
+	var x xliff20
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return gettext.Document{}, ConversionError{Reason: "Unable to read XLIFF 2.0.", UnderlyingError: err}
+	}
+	if len(x.Files) != 1 {
+		return gettext.Document{}, ConversionError{Reason: fmt.Sprint("Expected exactly one file, but found ", len(x.Files))}
+	}
+	file := x.Files[0]
+
+	var headerValue string
+	if file.Notes != nil {
+		for _, note := range file.Notes.Notes {
+			if note.Category == headerType {
+				headerValue = note.Text
+			}
+		}
+	}
+
+	units := make([]poUnit, 0, len(file.Elements))
+	for _, element := range file.Elements {
+		unit := poUnit{}
+		readXliff20Notes(&unit, element.Notes)
+
+		switch element.XMLName.Local {
+		case "unit":
+			if err := readXliff20Unit(&unit, &element); err != nil {
+				return gettext.Document{}, err
+			}
+		case "group":
+			unit.key.IsPlural = true
+			for _, u := range element.Units {
+				if err := readXliff20Unit(&unit, &u); err != nil {
+					return gettext.Document{}, err
+				}
+			}
+		default:
+			continue
+		}
+
+		units = append(units, unit)
+	}
+
+	return documentFromUnits(headerValue, x.TrgLang, units)
+}
+
+func readXliff20Unit(unit *poUnit, element *xliff20Element) error {
+	if len(element.Segments) == 0 {
+		return ConversionError{ID: element.ID, Reason: "Found a unit without a segment."}
+	}
+
+	// units split into multiple segments are joined back together
+	var source, target string
+	for _, segment := range element.Segments {
+		source += segment.Source
+		if segment.Target != nil {
+			target += *segment.Target
+		}
+		if segment.SubState == xliff20SubStateFuzzy && !unit.header.IsFuzzy() {
+			unit.header.Flags = append([]string{"fuzzy"}, unit.header.Flags...)
+		}
+	}
+
+	unit.sources = append(unit.sources, source)
+	unit.targets = append(unit.targets, target)
+	return nil
+}
+
+func readXliff20Notes(unit *poUnit, notes *xliff20Notes) {
+	if notes == nil {
+		return
+	}
+
+	for _, note := range notes.Notes {
+		switch note.Category {
+		case msgctxtType:
+			unit.key.IsContextual = true
+			unit.key.Context = note.Text
+		case msgidPluralType:
+			unit.key.PluralId = note.Text
+		case flagsType:
+			unit.header.Flags = append(unit.header.Flags, splitFlags(note.Text)...)
+		case xliff20LocationCategory:
+			unit.header.References = append(unit.header.References, note.Text)
+		case xliff20DeveloperCategory:
+			unit.header.ExtractedComments = append(unit.header.ExtractedComments, note.Text)
+		default:
+			// notes are usually from translators, so any that aren't marked otherwise are kept as translator comments
+			unit.header.TranslatorComments = append(unit.header.TranslatorComments, note.Text)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package xliff_test
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Timiz0r/golocalization/gettext"
+	"github.com/Timiz0r/golocalization/xliff"
+)
+
+const documentText = `msgid ""
+msgstr ""
+"Language: ru\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+# checked by someone
+#. shown on the main screen
+#: main.go:12 other.go
+#, fuzzy, c-format
+msgid "Open %s"
+msgstr "Открыть %s"
+
+msgctxt "verb"
+msgid "Open"
+msgstr ""
+
+#: files.go:3
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+
+msgid "  spaced <b>&</b>\n"
+msgstr "  пробелы <b>&</b>\n"
+
+#~ msgid "old"
+#~ msgstr "старый"
+`
+
+func TestXliff12_RoundTrip(t *testing.T) {
+	testRoundTrip(t, xliff.WriteXliff12, xliff.ReadXliff12, `state="needs-review-translation"`, `restype="x-gettext-plurals"`)
+}
+
+func TestXliff20_RoundTrip(t *testing.T) {
+	testRoundTrip(t, xliff.WriteXliff20, xliff.ReadXliff20, `subState="po:fuzzy"`, `type="po:plural"`)
+}
+
+func TestRead_DetectsVersion(t *testing.T) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+	for _, write := range []func(io.Writer, *gettext.Document, xliff.Options) error{xliff.WriteXliff12, xliff.WriteXliff20} {
+		var buf bytes.Buffer
+		if err := write(&buf, &doc, xliff.Options{}); err != nil {
+			t.Fatal("Error writing XLIFF: ", err)
+		}
+		read, err := xliff.Read(&buf)
+		if err != nil {
+			t.Fatal("Error reading XLIFF: ", err)
+		}
+		verifyEntries(t, &doc, &read)
+	}
+
+	_, err = xliff.Read(strings.NewReader(`<xliff version="3.0"></xliff>`))
+	if _, ok := err.(xliff.ConversionError); !ok {
+		t.Errorf("Expected %T but got %T: %+v", xliff.ConversionError{}, err, err)
+	}
+}
+
+func testRoundTrip(
+	t *testing.T,
+	write func(io.Writer, *gettext.Document, xliff.Options) error,
+	read func(io.Reader) (gettext.Document, error),
+	expectedStrings ...string) {
+	doc, err := gettext.ParseDocumentString(documentText)
+	if err != nil {
+		t.Fatal("Error parsing document: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, &doc, xliff.Options{}); err != nil {
+		t.Fatal("Error writing XLIFF: ", err)
+	}
+	for _, s := range expectedStrings {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected XLIFF to contain %v:\n%v", s, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "старый") {
+		t.Error("Expected obsolete entries to be skipped.")
+	}
+
+	readDoc, err := read(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Error reading XLIFF: %v\n%v", err, buf.String())
+	}
+	if readDoc.Header.Tag != doc.Header.Tag || readDoc.Entries[0].Value != doc.Entries[0].Value {
+		t.Errorf("Expected header %v, got %v.", doc.Entries[0].Value, readDoc.Entries[0].Value)
+	}
+	verifyEntries(t, &doc, &readDoc)
+}
+
+func verifyEntries(t *testing.T, expectedDoc *gettext.Document, actualDoc *gettext.Document) {
+	// minus the obsolete entry
+	expectedEntries := expectedDoc.Entries[1 : len(expectedDoc.Entries)-1]
+	actualEntries := actualDoc.Entries[1:]
+	if len(actualEntries) != len(expectedEntries) {
+		t.Fatalf("Expected %v entries, got %v.", len(expectedEntries), len(actualEntries))
+	}
+
+	for i, expected := range expectedEntries {
+		actual := actualEntries[i]
+		if actual.EntryKey != expected.EntryKey || actual.Value != expected.Value || !slices.Equal(actual.PluralValues, expected.PluralValues) {
+			t.Errorf("Expected entry %+v %v %v, got %+v %v %v.",
+				expected.EntryKey, expected.Value, expected.PluralValues, actual.EntryKey, actual.Value, actual.PluralValues)
+		}
+
+		if !slices.Equal(actual.Header.TranslatorComments, expected.Header.TranslatorComments) ||
+			!slices.Equal(actual.Header.ExtractedComments, expected.Header.ExtractedComments) ||
+			!slices.Equal(actual.Header.References, expected.Header.References) ||
+			!slices.Equal(actual.Header.Flags, expected.Header.Flags) {
+			t.Errorf("Expected entry header %+v, got %+v.", expected.Header, actual.Header)
+		}
+	}
+}